@@ -38,6 +38,47 @@ func GenerateMintTxData(tokenAddress string, recipientAddress string, amount *bi
 
 }
 
+// GenerateTransferTokenOwnershipTxData generates bridge call, transferring ownership of wrapped token to newOwner
+func GenerateTransferTokenOwnershipTxData(tokenAddress string, newOwner string) ([]byte, error) {
+
+	abi, err := NewABI([]byte(BRIDGE_ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	method := "transferTokenOwnership"
+	token := common.HexToAddress(tokenAddress)
+	owner := common.HexToAddress(newOwner)
+
+	data, err := abi.Pack(method, token, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+
+}
+
+// GenerateRenounceTokenOwnershipTxData generates bridge call, renouncing ownership of wrapped token
+func GenerateRenounceTokenOwnershipTxData(tokenAddress string) ([]byte, error) {
+
+	abi, err := NewABI([]byte(BRIDGE_ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	method := "renounceTokenOwnership"
+	token := common.HexToAddress(tokenAddress)
+
+	data, err := abi.Pack(method, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+
+}
+
 // UnpackBurnTxInputData unpacks bridge tx input data in hex format (without 0x)
 func UnpackBurnTxInputData(data string) (*BurnData, error) {
 
@@ -0,0 +1,108 @@
+package abiutil
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LockData is ERC-20 transfer of token in lock mode to the safe, with accumulate destination appended to the input data
+type LockData struct {
+	To          common.Address
+	Amount      *big.Int
+	Destination string
+}
+
+const ERC20_ABI = "[{\"inputs\":[],\"name\":\"decimals\",\"outputs\":[{\"internalType\":\"uint8\",\"name\":\"\",\"type\":\"uint8\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"symbol\",\"outputs\":[{\"internalType\":\"string\",\"name\":\"\",\"type\":\"string\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"balanceOf\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"amount\",\"type\":\"uint256\"}],\"name\":\"transfer\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"from\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"to\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"value\",\"type\":\"uint256\"}],\"name\":\"Transfer\",\"type\":\"event\"}]"
+
+// GenerateTransferTxData generates ERC-20 transfer input data, used by gnosis safe to unlock tokens
+func GenerateTransferTxData(recipientAddress string, amount *big.Int) ([]byte, error) {
+
+	abi, err := NewABI([]byte(ERC20_ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	method := "transfer"
+	recipient := common.HexToAddress(recipientAddress)
+
+	data, err := abi.Pack(method, recipient, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+
+}
+
+// GenerateLockTxData generates ERC-20 transfer input data, locking tokens in the safe for accumulate destination
+// Destination is appended after transfer arguments, ERC-20 contracts ignore it
+func GenerateLockTxData(safeAddress string, amount *big.Int, destination string) ([]byte, error) {
+
+	data, err := GenerateTransferTxData(safeAddress, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, []byte(destination)...), nil
+
+}
+
+// UnpackLockTxInputData unpacks ERC-20 transfer input data with appended accumulate destination
+func UnpackLockTxInputData(data []byte) (*LockData, error) {
+
+	abi, err := NewABI([]byte(ERC20_ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	method := abi.Methods["transfer"]
+
+	// selector + 2 arguments
+	argsLen := 4 + 2*32
+
+	if len(data) < argsLen || !bytes.Equal(data[:4], method.ID) {
+		return nil, fmt.Errorf("input data is not ERC-20 transfer")
+	}
+
+	args, err := method.Inputs.Unpack(data[4:argsLen])
+	if err != nil {
+		return nil, err
+	}
+
+	destination := data[argsLen:]
+	if len(destination) == 0 || !utf8.Valid(destination) {
+		return nil, fmt.Errorf("no accumulate destination in transfer input data")
+	}
+
+	lock := &LockData{
+		To:          args[0].(common.Address),
+		Amount:      args[1].(*big.Int),
+		Destination: string(destination),
+	}
+
+	return lock, nil
+
+}
+
+// RefundReference identifies the lock transfer, which is refunded, so that the same lock is not refunded twice
+// Log index has fixed width, so that reference of one log is not a prefix of reference of another
+func RefundReference(txid common.Hash, logIndex uint) []byte {
+	return []byte(fmt.Sprintf("refund:%s:%016x", txid.Hex(), logIndex))
+}
+
+// GenerateRefundTxData generates ERC-20 transfer input data, returning locked tokens to the sender
+// Reference of the refunded lock transfer is appended after transfer arguments, ERC-20 contracts ignore it
+func GenerateRefundTxData(recipientAddress string, amount *big.Int, txid common.Hash, logIndex uint) ([]byte, error) {
+
+	data, err := GenerateTransferTxData(recipientAddress, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(data, RefundReference(txid, logIndex)...), nil
+
+}
@@ -0,0 +1,80 @@
+package abiutil
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateTransferTx(t *testing.T) {
+
+	want := "0xa9059cbb000000000000000000000000c6386b0a95b60bcea480c876e3b1f9adb5b853140000000000000000000000000000000000000000000000000000000005f5e100"
+
+	recipient := "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"
+	amount := &big.Int{}
+	amount.SetInt64(1e8)
+
+	got, err := GenerateTransferTxData(recipient, amount)
+	assert.NoError(t, err)
+
+	assert.Equal(t, want, hexutil.Encode(got))
+
+}
+
+func TestLockTxData(t *testing.T) {
+
+	safe := "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"
+	amount := big.NewInt(1e8)
+
+	data, err := GenerateLockTxData(safe, amount, "acc://test.acme/tokens")
+	assert.NoError(t, err)
+
+	// TEST 1: destination is appended to transfer input data
+	lock, err := UnpackLockTxInputData(data)
+	assert.NoError(t, err)
+	assert.Equal(t, safe, lock.To.Hex())
+	assert.Equal(t, amount, lock.Amount)
+	assert.Equal(t, "acc://test.acme/tokens", lock.Destination)
+
+	// TEST 2: plain transfer has no destination
+	data, err = GenerateTransferTxData(safe, amount)
+	assert.NoError(t, err)
+	_, err = UnpackLockTxInputData(data)
+	assert.Error(t, err)
+
+	// TEST 3: other methods are not locks
+	data[0] = 0
+	_, err = UnpackLockTxInputData(append(data, []byte("acc://test.acme/tokens")...))
+	assert.Error(t, err)
+
+}
+
+func TestRefundTxData(t *testing.T) {
+
+	sender := "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"
+	amount := big.NewInt(1e8)
+	txid := common.HexToHash("0x6d1b3b1d8e7d4f4b8a3e6c9f0a2b5d7e9c1f3a5b7d9e1f3a5c7e9b1d3f5a7c9e")
+
+	data, err := GenerateRefundTxData(sender, amount, txid, 3)
+	assert.NoError(t, err)
+
+	// TEST 1: refund is a transfer of the amount to the sender
+	transfer, err := GenerateTransferTxData(sender, amount)
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(data, transfer))
+
+	// TEST 2: reference of the lock is appended
+	assert.Equal(t, "refund:"+txid.Hex()+":0000000000000003", string(data[len(transfer):]))
+
+	// TEST 3: refunds of other logs of the same tx have other references
+	assert.False(t, bytes.Contains(data, RefundReference(txid, 4)))
+
+	data, err = GenerateRefundTxData(sender, amount, txid, 30)
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(data, RefundReference(txid, 3)))
+
+}
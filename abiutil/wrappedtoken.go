@@ -0,0 +1,37 @@
+package abiutil
+
+import (
+	"github.com/AccumulateNetwork/bridge/binding"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GeneratePauseTxData generates wrapped token pause input data
+func GeneratePauseTxData() ([]byte, error) {
+	return packWrappedToken("pause")
+}
+
+// GenerateUnpauseTxData generates wrapped token unpause input data
+func GenerateUnpauseTxData() ([]byte, error) {
+	return packWrappedToken("unpause")
+}
+
+// GenerateTransferOwnershipTxData generates wrapped token transferOwnership input data
+func GenerateTransferOwnershipTxData(newOwner string) ([]byte, error) {
+	return packWrappedToken("transferOwnership", common.HexToAddress(newOwner))
+}
+
+func packWrappedToken(method string, args ...interface{}) ([]byte, error) {
+
+	abi, err := NewABI([]byte(binding.WrappedTokenMetaData.ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := abi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+
+}
@@ -15,26 +15,37 @@ import (
 )
 
 const (
-	ACC_KEYPAGE                 = "1"       // bridge ADI keypage
-	ACC_LEADER                  = "leader"  // data account: current leader (pubkeyhash)
-	ACC_TOKEN_REGISTRY          = "tokens"  // data account: token registry (accumulate token address, evm token address, evm chainid)
-	ACC_BRIDGE_FEES             = "fees"    // data account: bridge fees
-	ACC_MINT_QUEUE              = "mint"    // data account: mint queue, {chainid}:mint
-	ACC_RELEASE_QUEUE           = "release" // data account: release queue, {chainid}:release
-	ACC_BRIDGE_STATUS           = "status"  // data account: status (1 = on, 0 = off)
-	TOKEN_REGISTRY_VERSION      = "v1"      // validate token registry data entries
-	MINT_QUEUE_VERSION          = "v1"      // validate burn events data entries
-	RELEASE_QUEUE_VERSION       = "v1"      // validate deposit list data entries
+	ACC_KEYPAGE                 = "1"          // bridge ADI keypage
+	ACC_LEADER                  = "leader"     // data account: current leader (pubkeyhash)
+	ACC_TOKEN_REGISTRY          = "tokens"     // data account: token registry (accumulate token address, evm token address, evm chainid)
+	ACC_BRIDGE_FEES             = "fees"       // data account: bridge fees
+	ACC_MINT_QUEUE              = "mint"       // data account: mint queue, {chainid}:mint
+	ACC_RELEASE_QUEUE           = "release"    // data account: release queue, {chainid}:release
+	ACC_BRIDGE_STATUS           = "status"     // data account: status (1 = on, 0 = off)
+	ACC_SIGNATURES              = "sigs"       // data account: gnosis safe tx signatures, {chainid}:sigs
+	ACC_HEARTBEAT               = "heartbeat"  // data account: signed liveness messages of bridge nodes
+	ACC_REJECTIONS              = "rejections" // data account: auditor rejections of pending queue entries, {chainid}:rejections
+	TOKEN_REGISTRY_VERSION      = "v1"         // validate token registry data entries
+	MINT_QUEUE_VERSION          = "v1"         // validate burn events data entries
+	RELEASE_QUEUE_VERSION       = "v1"         // validate deposit list data entries
+	SIGNATURES_VERSION          = "v1"         // validate safe tx signature data entries
+	HEARTBEAT_VERSION           = "v1"         // validate heartbeat data entries
+	REJECTIONS_VERSION          = "v1"         // validate rejection data entries
 	SIGNATURE_TYPE              = "ed25519"
 	ZERO_HASH                   = "0000000000000000000000000000000000000000000000000000000000000000"
 	TX_TYPE_SYNTH_TOKEN_DEPOSIT = "syntheticDepositTokens"
 	TX_TYPE_SEND_TOKENS         = "sendTokens"
+	TX_TYPE_ISSUE_TOKENS        = "issueTokens"
+	TX_TYPE_BURN_TOKENS         = "burnTokens"
+	ACME_PRECISION              = 8 // ACME token precision
+	CREDIT_PRECISION            = 2 // key page credit balance precision
 )
 
 type AccumulateClient struct {
 	API           string
 	ADI           string
 	Signer        string
+	SigsSigner    string // key page to publish safe tx signatures with
 	PrivateKey    ed25519.PrivateKey
 	PublicKey     ed25519.PublicKey
 	PublicKeyHash []byte
@@ -92,6 +103,11 @@ func NewAccumulateClient(conf *config.Config) (*AccumulateClient, error) {
 	c.ADI = conf.ACME.BridgeADI
 	c.Signer = filepath.Join(conf.ACME.BridgeADI, conf.ACME.KeyBook, ACC_KEYPAGE)
 
+	c.SigsSigner = c.Signer
+	if conf.ACME.SignaturesKeyPage != "" {
+		c.SigsSigner = filepath.Join(conf.ACME.BridgeADI, conf.ACME.KeyBook, conf.ACME.SignaturesKeyPage)
+	}
+
 	if conf.ACME.PrivateKey == "" {
 		return nil, fmt.Errorf("received empty privateKey from config: %s", conf.ACME.PrivateKey)
 	}
@@ -3,6 +3,7 @@ package accumulate
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -43,6 +44,14 @@ type Token struct {
 	Precision   int64  `json:"precision"`
 }
 
+// ErrNotTokenAccount is returned by QueryTokenAccount, if the account exists, but is not a token account
+var ErrNotTokenAccount = errors.New("not a token account")
+
+const (
+	ACCOUNT_TYPE_TOKEN      = "tokenAccount"
+	ACCOUNT_TYPE_LITE_TOKEN = "liteTokenAccount"
+)
+
 type TokenAccount struct {
 	Type        string `json:"type" validate:"required,oneof=tokenAccount liteTokenAccount"`
 	Authorities []*URL `json:"authorities"`
@@ -117,6 +126,7 @@ type QueryDataResponse struct {
 
 type QueryDataSetResponse struct {
 	Items []*DataEntry `json:"items"`
+	Total int64        `json:"total"`
 	//LastBlockTime *time.Time   `json:"lastBlockTime" validate:"required,notOlderThanOneMinute"`
 }
 
@@ -141,6 +151,14 @@ type QueryTxHistoryResponse struct {
 	Items []*QueryTokenTxResponse `json:"items"`
 }
 
+type DescribeResponse struct {
+	Values struct {
+		Oracle struct {
+			Price uint64 `json:"price" validate:"required"`
+		} `json:"oracle"`
+	} `json:"values"`
+}
+
 // QueryADI gets Token info
 func (c *AccumulateClient) QueryADI(adi *Params) (*QueryADIResponse, error) {
 
@@ -244,6 +262,15 @@ func (c *AccumulateClient) QueryTokenAccount(account *Params) (*QueryTokenAccoun
 		return nil, fmt.Errorf("can not unmarshal api response: %s", err)
 	}
 
+	// ADI, data account, etc.
+	if accountResp.Data == nil || (accountResp.Data.Type != ACCOUNT_TYPE_TOKEN && accountResp.Data.Type != ACCOUNT_TYPE_LITE_TOKEN) {
+		accountType := ""
+		if accountResp.Data != nil {
+			accountType = accountResp.Data.Type
+		}
+		return nil, fmt.Errorf("%w: %s is %q", ErrNotTokenAccount, account.URL, accountType)
+	}
+
 	err = c.Validate.Struct(accountResp)
 	if err != nil {
 		fmt.Println(err)
@@ -427,6 +454,34 @@ func (c *AccumulateClient) QueryPendingChain(account *Params) (*QueryPendingChai
 
 }
 
+// QueryOracle gets current ACME oracle price from network description
+func (c *AccumulateClient) QueryOracle() (uint64, error) {
+
+	describeResp := &DescribeResponse{}
+
+	resp, err := c.Client.Call(context.Background(), "describe")
+	if err != nil {
+		return 0, err
+	}
+
+	if resp.Error != nil {
+		return 0, resp.Error
+	}
+
+	err = resp.GetObject(describeResp)
+	if err != nil {
+		return 0, fmt.Errorf("can not unmarshal api response: %s", err)
+	}
+
+	err = c.Validate.Struct(describeResp)
+	if err != nil {
+		return 0, err
+	}
+
+	return describeResp.Values.Oracle.Price, nil
+
+}
+
 // Create calls "execute-direct" tx on Accumulate
 func (c *AccumulateClient) ExecuteDirect(params *Params) (*ExecuteDirectResponse, error) {
 
@@ -11,7 +11,8 @@ import (
 )
 
 // SendTokens generates sendTokens tx for `execute-direct` API method
-func (c *AccumulateClient) SendTokens(to string, amount int64, tokenURL string, chainId int64) (string, error) {
+// memo is written into tx header, e.g. reference to the EVM burn (see GenerateReleaseMemo)
+func (c *AccumulateClient) SendTokens(to string, amount int64, tokenURL string, chainId int64, memo string) (string, error) {
 
 	// query token
 	token, err := c.QueryToken(&Params{URL: tokenURL})
@@ -38,7 +39,92 @@ func (c *AccumulateClient) SendTokens(to string, amount int64, tokenURL string,
 	amountBigInt := *big.NewInt(amount)
 	payload.AddRecipient(accumulateUrl, &amountBigInt)
 
-	env, err := c.buildEnvelope(fromTokenAccount, payload)
+	env, err := c.buildEnvelope(fromTokenAccount, payload, memo)
+	if err != nil {
+		return "", err
+	}
+
+	params := &Params{Envelope: env}
+
+	resp, err := c.ExecuteDirect(params)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Txid, nil
+
+}
+
+// IssueTokens generates issueTokens tx for `execute-direct` API method
+// tokenURL is the token issuer, bridge ADI key book should be its authority
+func (c *AccumulateClient) IssueTokens(to string, amount int64, tokenURL string, memo string) (string, error) {
+
+	// tx body
+	payload := new(protocol.IssueTokens)
+
+	toUrl, err := accurl.Parse(to)
+	if err != nil {
+		return "", err
+	}
+
+	amountBigInt := *big.NewInt(amount)
+	payload.To = append(payload.To, &protocol.TokenRecipient{Url: protocol.AccountUrl(toUrl.Authority, toUrl.Path), Amount: amountBigInt})
+
+	env, err := c.buildEnvelope(tokenURL, payload, memo)
+	if err != nil {
+		return "", err
+	}
+
+	params := &Params{Envelope: env}
+
+	resp, err := c.ExecuteDirect(params)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Txid, nil
+
+}
+
+// BurnTokens generates burnTokens tx for `execute-direct` API method
+func (c *AccumulateClient) BurnTokens(from string, amount int64, memo string) (string, error) {
+
+	// tx body
+	payload := new(protocol.BurnTokens)
+	payload.Amount = *big.NewInt(amount)
+
+	env, err := c.buildEnvelope(from, payload, memo)
+	if err != nil {
+		return "", err
+	}
+
+	params := &Params{Envelope: env}
+
+	resp, err := c.ExecuteDirect(params)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Txid, nil
+
+}
+
+// AddCredits generates addCredits tx for `execute-direct` API method
+// amount is in ACME base units, oracle is the current ACME oracle price
+func (c *AccumulateClient) AddCredits(from string, recipient string, amount int64, oracle uint64) (string, error) {
+
+	recipientUrl, err := accurl.Parse(recipient)
+	if err != nil {
+		return "", err
+	}
+
+	// tx body
+	payload := new(protocol.AddCredits)
+	payload.Recipient = protocol.AccountUrl(recipientUrl.Authority, recipientUrl.Path)
+	payload.Amount = *big.NewInt(amount)
+	payload.Oracle = oracle
+
+	env, err := c.buildEnvelope(from, payload, "")
 	if err != nil {
 		return "", err
 	}
@@ -65,7 +151,34 @@ func (c *AccumulateClient) RemoteTransaction(from string, txhash string) (string
 	}
 	payload.Hash = *byte32(hash)
 
-	env, err := c.buildEnvelope(from, payload)
+	env, err := c.buildEnvelope(from, payload, "")
+	if err != nil {
+		return "", err
+	}
+
+	params := &Params{Envelope: env}
+
+	resp, err := c.ExecuteDirect(params)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.Txid, nil
+
+}
+
+// RejectTransaction generates remote tx for `execute-direct` API method with signature voting against the pending tx
+func (c *AccumulateClient) RejectTransaction(from string, txhash string) (string, error) {
+
+	// tx body
+	payload := new(protocol.RemoteTransaction)
+	hash, err := hex.DecodeString(txhash)
+	if err != nil {
+		return "", err
+	}
+	payload.Hash = *byte32(hash)
+
+	env, err := c.buildEnvelopeWith(from, c.Signer, rejectSigner{signing.PrivateKey(c.PrivateKey)}, payload, "")
 	if err != nil {
 		return "", err
 	}
@@ -81,8 +194,25 @@ func (c *AccumulateClient) RemoteTransaction(from string, txhash string) (string
 
 }
 
+// rejectSigner signs with the private key, the vote is set before signing, as it is part of the signed metadata
+type rejectSigner struct {
+	signing.PrivateKey
+}
+
+func (s rejectSigner) SetPublicKey(sig protocol.Signature) error {
+	if sig, ok := sig.(*protocol.ED25519Signature); ok {
+		sig.Vote = protocol.VoteTypeReject
+	}
+	return s.PrivateKey.SetPublicKey(sig)
+}
+
 // WriteData generates writeData tx for `execute-direct` API method
 func (c *AccumulateClient) WriteData(dataAccount string, content [][]byte) (string, error) {
+	return c.WriteDataAs(dataAccount, c.Signer, content)
+}
+
+// WriteDataAs generates writeData tx signed with the given key page for `execute-direct` API method
+func (c *AccumulateClient) WriteDataAs(dataAccount string, signer string, content [][]byte) (string, error) {
 
 	// tx body
 	entry := new(protocol.DoubleHashDataEntry)
@@ -91,7 +221,7 @@ func (c *AccumulateClient) WriteData(dataAccount string, content [][]byte) (stri
 	payload := new(protocol.WriteData)
 	payload.Entry = entry
 
-	env, err := c.buildEnvelope(dataAccount, payload)
+	env, err := c.buildEnvelopeAs(dataAccount, signer, payload, "")
 	if err != nil {
 		return "", err
 	}
@@ -107,7 +237,15 @@ func (c *AccumulateClient) WriteData(dataAccount string, content [][]byte) (stri
 
 }
 
-func (c *AccumulateClient) buildEnvelope(from string, payload protocol.TransactionBody) (*protocol.Envelope, error) {
+func (c *AccumulateClient) buildEnvelope(from string, payload protocol.TransactionBody, memo string) (*protocol.Envelope, error) {
+	return c.buildEnvelopeAs(from, c.Signer, payload, memo)
+}
+
+func (c *AccumulateClient) buildEnvelopeAs(from string, signerPage string, payload protocol.TransactionBody, memo string) (*protocol.Envelope, error) {
+	return c.buildEnvelopeWith(from, signerPage, signing.PrivateKey(c.PrivateKey), payload, memo)
+}
+
+func (c *AccumulateClient) buildEnvelopeWith(from string, signerPage string, keySigner signing.Signer, payload protocol.TransactionBody, memo string) (*protocol.Envelope, error) {
 
 	fromUrl, err := accurl.Parse(from)
 	if err != nil {
@@ -116,20 +254,20 @@ func (c *AccumulateClient) buildEnvelope(from string, payload protocol.Transacti
 
 	principal := protocol.AccountUrl(fromUrl.Authority, fromUrl.Path)
 
-	signerUrl, err := accurl.Parse(c.Signer)
+	signerUrl, err := accurl.Parse(signerPage)
 	if err != nil {
 		return nil, err
 	}
 
 	keypage := protocol.AccountUrl(signerUrl.Authority, signerUrl.Path)
 
-	kpData, err := c.QueryKeyPage(&Params{URL: c.Signer})
+	kpData, err := c.QueryKeyPage(&Params{URL: signerPage})
 	if err != nil {
 		return nil, err
 	}
 
 	signer := new(signing.Builder)
-	signer.SetPrivateKey(c.PrivateKey)
+	signer.SetSigner(keySigner)
 	signer.SetTimestampToNow()
 	signer.SetVersion(kpData.Data.Version)
 	signer.SetType(protocol.SignatureTypeED25519)
@@ -138,6 +276,7 @@ func (c *AccumulateClient) buildEnvelope(from string, payload protocol.Transacti
 	txn := new(protocol.Transaction)
 	txn.Body = payload
 	txn.Header.Principal = principal
+	txn.Header.Memo = memo
 
 	sig, err := signer.Initiate(txn)
 	if err != nil {
@@ -1 +1,40 @@
 package accumulate
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/accumulatenetwork/accumulate/pkg/client/signing"
+	"gitlab.com/accumulatenetwork/accumulate/protocol"
+)
+
+func TestRejectSigner(t *testing.T) {
+
+	privKey, err := hex.DecodeString("7d36bbb9f6c36bd4883095ae12795a85def0f3027332e1930fbd4626c8f8ac921fece78f587776b6f178cbb1437ff0102f039a0872ec89766da084be84221cc8")
+	assert.NoError(t, err)
+
+	txn := new(protocol.Transaction)
+	txn.Header.Principal = protocol.AccountUrl("bridge.acme", "audit:1:release")
+	txn.Body = &protocol.RemoteTransaction{Hash: [32]byte{1}}
+
+	signer := new(signing.Builder)
+	signer.SetSigner(rejectSigner{signing.PrivateKey(privKey)})
+	signer.SetTimestampToNow()
+	signer.SetVersion(1)
+	signer.SetType(protocol.SignatureTypeED25519)
+	signer.SetUrl(protocol.AccountUrl("bridge.acme", "book", "1"))
+
+	sig, err := signer.Initiate(txn)
+	assert.NoError(t, err)
+
+	// TEST 1: signature votes against the tx and the vote is signed
+	ed25519Sig, ok := sig.(*protocol.ED25519Signature)
+	assert.True(t, ok)
+	assert.Equal(t, protocol.VoteTypeReject, ed25519Sig.Vote)
+	assert.True(t, ed25519Sig.Verify(nil, txn.GetHash()))
+
+	ed25519Sig.Vote = protocol.VoteTypeAccept
+	assert.False(t, ed25519Sig.Verify(nil, txn.GetHash()))
+
+}
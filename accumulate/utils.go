@@ -1,11 +1,23 @@
 package accumulate
 
 import (
+	"errors"
+	"fmt"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"unsafe"
+
+	"github.com/ybbus/jsonrpc/v3"
+)
+
+const (
+	API_ERR_CODE_NOT_FOUND          = -32807 // Accumulate API: not found
+	API_ERR_CODE_PROTOCOL_NOT_FOUND = -33404 // Accumulate API: protocol error with status not found
 )
 
+const RELEASE_MEMO_PREFIX = "evm" // release tx memo: evm:{chainId}:{burn txid}:{log index}
+
 // Generate bridge token account in format {chainId}-{symbol}
 func GenerateTokenAccount(adi string, chainId int64, symbol string) string {
 	return filepath.Join(adi, strconv.Itoa(int(chainId))+"-"+symbol)
@@ -31,6 +43,42 @@ func GenerateDataEntry(account string, entryhash string) string {
 	return filepath.Join(entryhash + "@" + account)
 }
 
+// Generate release tx memo in format evm:{chainId}:{txid}:{logIndex}
+func GenerateReleaseMemo(chainId int64, txid string, logIndex uint64) string {
+	return strings.Join([]string{RELEASE_MEMO_PREFIX, strconv.FormatInt(chainId, 10), strings.ToLower(txid), strconv.FormatUint(logIndex, 10)}, ":")
+}
+
+// ParseReleaseMemo parses release tx memo into EVM chainId, burn txid and log index
+func ParseReleaseMemo(memo string) (int64, string, uint64, error) {
+
+	parts := strings.Split(memo, ":")
+	if len(parts) != 4 || parts[0] != RELEASE_MEMO_PREFIX {
+		return 0, "", 0, fmt.Errorf("invalid release memo %q", memo)
+	}
+
+	chainId, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, "", 0, fmt.Errorf("invalid chainId in release memo %q", memo)
+	}
+
+	logIndex, err := strconv.ParseUint(parts[3], 10, 64)
+	if err != nil {
+		return 0, "", 0, fmt.Errorf("invalid log index in release memo %q", memo)
+	}
+
+	return chainId, parts[2], logIndex, nil
+
+}
+
+// IsNotFound returns true if Accumulate API responded that the requested record does not exist
+func IsNotFound(err error) bool {
+	var rpcErr *jsonrpc.RPCError
+	if !errors.As(err, &rpcErr) {
+		return false
+	}
+	return rpcErr.Code == API_ERR_CODE_NOT_FOUND || rpcErr.Code == API_ERR_CODE_PROTOCOL_NOT_FOUND
+}
+
 func byte32(s []byte) (a *[32]byte) {
 	if len(a) <= len(s) {
 		a = (*[len(a)]byte)(unsafe.Pointer(&s[0]))
@@ -1 +1,32 @@
 package accumulate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReleaseMemo(t *testing.T) {
+
+	txid := "0x8d2F0FBE0A2A9B2E4bbd0F7D5b5e1C7d8b1c0a4e16f9c0f6b9e6f3c2a1d0e9f8"
+
+	memo := GenerateReleaseMemo(1, txid, 3)
+	assert.Equal(t, "evm:1:0x8d2f0fbe0a2a9b2e4bbd0f7d5b5e1c7d8b1c0a4e16f9c0f6b9e6f3c2a1d0e9f8:3", memo)
+
+	chainId, parsedTxID, logIndex, err := ParseReleaseMemo(memo)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), chainId)
+	assert.Equal(t, "0x8d2f0fbe0a2a9b2e4bbd0f7d5b5e1c7d8b1c0a4e16f9c0f6b9e6f3c2a1d0e9f8", parsedTxID)
+	assert.Equal(t, uint64(3), logIndex)
+
+	// invalid memos
+	_, _, _, err = ParseReleaseMemo("")
+	assert.Error(t, err)
+
+	_, _, _, err = ParseReleaseMemo("evm:one:0x00:3")
+	assert.Error(t, err)
+
+	_, _, _, err = ParseReleaseMemo("btc:1:0x00:3")
+	assert.Error(t, err)
+
+}
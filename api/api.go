@@ -10,6 +10,8 @@ import (
 	"github.com/AccumulateNetwork/bridge/accumulate"
 	"github.com/AccumulateNetwork/bridge/config"
 	"github.com/AccumulateNetwork/bridge/global"
+	"github.com/AccumulateNetwork/bridge/schema"
+	"github.com/AccumulateNetwork/bridge/utils"
 	"go.neonxp.dev/jsonrpc2/rpc"
 	"go.neonxp.dev/jsonrpc2/transport"
 )
@@ -44,7 +46,11 @@ func StartAPI(conf *config.Config) error {
 
 	s.r.Register("fees", rpc.H(s.Fees))
 	s.r.Register("tokens", rpc.H(s.Tokens))
+	s.r.Register("token-changes", rpc.H(s.TokenChanges))
 	s.r.Register("token-account", rpc.H(s.TokenAccount))
+	s.r.Register("credits", rpc.H(s.Credits))
+	s.r.Register("censorship", rpc.H(s.Censorship))
+	s.r.Register("metrics", rpc.H(s.Metrics))
 
 	if err := s.r.Run(ctx); err != nil {
 		log.Fatal(err)
@@ -58,7 +64,29 @@ func (s *Server) Fees(ctx context.Context, _ *NoArgs) (interface{}, error) {
 }
 
 func (s *Server) Tokens(ctx context.Context, _ *NoArgs) (interface{}, error) {
-	return &global.Tokens, nil
+	return &schema.Tokens{ChainID: global.Tokens.ChainID, Items: utils.GetTokens()}, nil
+}
+
+func (s *Server) TokenChanges(ctx context.Context, _ *NoArgs) (interface{}, error) {
+	global.TokensLock.RLock()
+	defer global.TokensLock.RUnlock()
+	return global.TokenChanges, nil
+}
+
+func (s *Server) Credits(ctx context.Context, _ *NoArgs) (interface{}, error) {
+	return &Credits{Balance: global.CreditBalance}, nil
+}
+
+func (s *Server) Censorship(ctx context.Context, _ *NoArgs) (interface{}, error) {
+	global.CensorshipLock.RLock()
+	defer global.CensorshipLock.RUnlock()
+	return global.Censorship, nil
+}
+
+func (s *Server) Metrics(ctx context.Context, _ *NoArgs) (interface{}, error) {
+	global.MetricsLock.RLock()
+	defer global.MetricsLock.RUnlock()
+	return global.Metrics, nil
 }
 
 func (s *Server) TokenAccount(ctx context.Context, url *URL) (interface{}, error) {
@@ -75,6 +103,10 @@ func (s *Server) TokenAccount(ctx context.Context, url *URL) (interface{}, error
 type NoArgs struct {
 }
 
+type Credits struct {
+	Balance float64 `json:"balance"`
+}
+
 type URL struct {
 	URL string `json:"url"`
 }
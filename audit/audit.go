@@ -0,0 +1,106 @@
+package audit
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AccumulateNetwork/bridge/schema"
+)
+
+const (
+	EVENT_DEPOSIT = "deposit"
+	EVENT_BURN    = "burn"
+)
+
+// Tracker keeps deposits and burns, which are not queued by the leader, and flags them once they stay unqueued for the window
+type Tracker struct {
+	Window  time.Duration
+	Flagged int64                        // number of events flagged since start
+	queues  map[string]map[string]*entry // unqueued events by queue and event key
+}
+
+type entry struct {
+	event   *schema.MissedEvent
+	flagged bool
+}
+
+// NewTracker creates tracker with the censorship window
+func NewTracker(window time.Duration) *Tracker {
+	return &Tracker{Window: window, queues: make(map[string]map[string]*entry)}
+}
+
+// Update replaces unqueued events of the queue with the latest scan. Events, which are no longer in the scan, were queued
+// and are forgotten. Returns events, which have just stayed unqueued for the window
+func (t *Tracker) Update(queue string, events []*schema.MissedEvent, now time.Time) []*schema.MissedEvent {
+
+	previous := t.queues[queue]
+	current := make(map[string]*entry)
+
+	var flagged []*schema.MissedEvent
+
+	for _, event := range events {
+
+		key := eventKey(event)
+
+		e := &entry{event: event}
+		event.FirstSeen = now
+		if seen, ok := previous[key]; ok {
+			event.FirstSeen = seen.event.FirstSeen
+			e.flagged = seen.flagged
+		}
+
+		if !e.flagged && now.Sub(event.FirstSeen) >= t.Window {
+			e.flagged = true
+			flagged = append(flagged, event)
+		}
+
+		current[key] = e
+
+	}
+
+	t.queues[queue] = current
+	t.Flagged += int64(len(flagged))
+
+	return flagged
+
+}
+
+// Report returns flagged events, which are still not queued, oldest first
+func (t *Tracker) Report() *schema.CensorshipReport {
+
+	report := &schema.CensorshipReport{Window: int64(t.Window / time.Minute), TotalFlagged: t.Flagged, Items: []*schema.MissedEvent{}}
+
+	for _, queue := range t.queues {
+		for _, e := range queue {
+			if !e.flagged {
+				continue
+			}
+			switch e.event.Type {
+			case EVENT_DEPOSIT:
+				report.MissedDeposits++
+			case EVENT_BURN:
+				report.MissedBurns++
+			}
+			report.Items = append(report.Items, e.event)
+		}
+	}
+
+	sort.Slice(report.Items, func(i, j int) bool {
+		if !report.Items[i].FirstSeen.Equal(report.Items[j].FirstSeen) {
+			return report.Items[i].FirstSeen.Before(report.Items[j].FirstSeen)
+		}
+		return eventKey(report.Items[i]) < eventKey(report.Items[j])
+	})
+
+	return report
+
+}
+
+// eventKey identifies deposit by its seq number and burn by its log position
+func eventKey(event *schema.MissedEvent) string {
+	if event.Type == EVENT_BURN {
+		return fmt.Sprintf("%s:%d:%d", event.Type, event.BlockHeight, event.LogIndex)
+	}
+	return fmt.Sprintf("%s:%d", event.Type, event.SeqNumber)
+}
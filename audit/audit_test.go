@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/bridge/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracker(t *testing.T) {
+
+	now := time.Unix(1700000000, 0)
+	window := 10 * time.Minute
+
+	tracker := NewTracker(window)
+
+	deposits := func() []*schema.MissedEvent {
+		return []*schema.MissedEvent{
+			{Type: EVENT_DEPOSIT, ID: "a", SeqNumber: 11},
+			{Type: EVENT_DEPOSIT, ID: "b", SeqNumber: 12},
+		}
+	}
+
+	burns := func() []*schema.MissedEvent {
+		return []*schema.MissedEvent{
+			{Type: EVENT_BURN, ID: "0x01", BlockHeight: 100, LogIndex: 3},
+		}
+	}
+
+	// TEST 1: unqueued events are not flagged within the window
+	assert.Empty(t, tracker.Update("mint", deposits(), now))
+	assert.Empty(t, tracker.Update("release", burns(), now.Add(5*time.Minute)))
+	assert.Empty(t, tracker.Report().Items)
+
+	// TEST 2: events are flagged once, when they reach the window
+	flagged := tracker.Update("mint", deposits(), now.Add(window))
+	assert.Len(t, flagged, 2)
+	assert.Equal(t, now, flagged[0].FirstSeen)
+	assert.Empty(t, tracker.Update("mint", deposits(), now.Add(window+time.Minute)))
+
+	report := tracker.Report()
+	assert.Equal(t, int64(10), report.Window)
+	assert.Equal(t, int64(2), report.MissedDeposits)
+	assert.Equal(t, int64(0), report.MissedBurns)
+	assert.Equal(t, int64(2), report.TotalFlagged)
+	assert.Equal(t, "a", report.Items[0].ID)
+
+	// TEST 3: queued events are forgotten, the total is kept
+	assert.Empty(t, tracker.Update("mint", deposits()[1:], now.Add(window+2*time.Minute)))
+	assert.Len(t, tracker.Update("release", burns(), now.Add(window+5*time.Minute)), 1)
+
+	report = tracker.Report()
+	assert.Equal(t, int64(1), report.MissedDeposits)
+	assert.Equal(t, int64(1), report.MissedBurns)
+	assert.Equal(t, int64(3), report.TotalFlagged)
+	assert.Equal(t, []string{"b", "0x01"}, []string{report.Items[0].ID, report.Items[1].ID})
+
+	// TEST 4: event, which is missed again after being queued, starts a new window
+	tracker.Update("mint", nil, now.Add(window+3*time.Minute))
+	assert.Empty(t, tracker.Update("mint", deposits(), now.Add(window+4*time.Minute)))
+
+}
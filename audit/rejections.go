@@ -0,0 +1,120 @@
+package audit
+
+import (
+	"strings"
+
+	"github.com/AccumulateNetwork/bridge/schema"
+)
+
+// Rejections are votes of bridge nodes against pending queue entries
+type Rejections []*schema.Rejection
+
+// HasRejected checks if the node has already rejected the entry
+func (r Rejections) HasRejected(node string, queue string, entryhash string) bool {
+
+	for _, rejection := range r {
+		if strings.EqualFold(rejection.Node, node) && matches(rejection, queue, entryhash) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// IsRejected checks if the entry is rejected by so many keys of the key page, that it can not reach the threshold anymore
+func (r Rejections) IsRejected(queue string, entryhash string, keys []string, threshold int64) bool {
+
+	rejected := make(map[string]bool)
+
+	for _, rejection := range r {
+		if !matches(rejection, queue, entryhash) {
+			continue
+		}
+		for _, key := range keys {
+			if strings.EqualFold(rejection.Node, key) {
+				rejected[strings.ToLower(key)] = true
+			}
+		}
+	}
+
+	return len(rejected) > 0 && int64(len(rejected)) > int64(len(keys))-threshold
+
+}
+
+// SafeTxHashes returns safe tx hashes of mint entries, recovered by the node as leader
+func (r Rejections) SafeTxHashes(node string) map[string]bool {
+
+	hashes := make(map[string]bool)
+
+	for _, rejection := range r {
+		if rejection.SafeTxHash != "" && strings.EqualFold(rejection.Node, node) {
+			hashes[strings.ToLower(rejection.SafeTxHash)] = true
+		}
+	}
+
+	return hashes
+
+}
+
+// BurnTxHashes returns burn txs of mint entries, recovered by the node as leader
+func (r Rejections) BurnTxHashes(node string) map[string]bool {
+
+	hashes := make(map[string]bool)
+
+	for _, rejection := range r {
+		if rejection.BurnTxHash != "" && strings.EqualFold(rejection.Node, node) {
+			hashes[strings.ToLower(rejection.BurnTxHash)] = true
+		}
+	}
+
+	return hashes
+
+}
+
+// RejectionCache keeps rejections by queue entry, so that rejections of settled entries are dropped
+type RejectionCache map[string]Rejections
+
+// Add adds rejection to the rejections of its entry
+func (c RejectionCache) Add(rejection *schema.Rejection) {
+	key := rejection.Queue + "/" + strings.ToLower(rejection.EntryHash)
+	c[key] = append(c[key], rejection)
+}
+
+// All returns rejections of all entries
+func (c RejectionCache) All() Rejections {
+
+	all := Rejections{}
+
+	for _, rejections := range c {
+		all = append(all, rejections...)
+	}
+
+	return all
+
+}
+
+// Prune drops rejections of settled entries, isSettled is called with the rejections of every entry.
+// Entries, which can not be checked, are kept
+func (c RejectionCache) Prune(isSettled func(rejections Rejections) (bool, error)) error {
+
+	for key, rejections := range c {
+
+		settled, err := isSettled(rejections)
+		if err != nil {
+			return err
+		}
+
+		if settled {
+			delete(c, key)
+		}
+
+	}
+
+	return nil
+
+}
+
+func matches(rejection *schema.Rejection, queue string, entryhash string) bool {
+	return rejection.Queue == queue && strings.EqualFold(rejection.EntryHash, entryhash)
+}
@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"testing"
+
+	"github.com/AccumulateNetwork/bridge/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRejections(t *testing.T) {
+
+	keys := []string{"aa", "bb", "cc"}
+
+	rejections := Rejections{
+		{Queue: "release", EntryHash: "01", Node: "AA"},
+		{Queue: "release", EntryHash: "01", Node: "aa"},
+		{Queue: "release", EntryHash: "02", Node: "aa"},
+		{Queue: "release", EntryHash: "02", Node: "bb"},
+		{Queue: "release", EntryHash: "03", Node: "dd"},
+		{Queue: "mint", EntryHash: "04", Node: "cc", SafeTxHash: "0xAB", BurnTxHash: "acc://AB@bridge.acme/1-TKN"},
+	}
+
+	// TEST 1: the same node is counted once
+	assert.True(t, rejections.HasRejected("aa", "release", "01"))
+	assert.False(t, rejections.IsRejected("release", "01", keys, 2))
+	assert.True(t, rejections.IsRejected("release", "01", keys, 3))
+
+	// TEST 2: entry can not reach the threshold
+	assert.True(t, rejections.IsRejected("release", "02", keys, 2))
+
+	// TEST 3: rejections of other queues and nodes, which are not on the key page, are not counted
+	assert.False(t, rejections.IsRejected("release", "03", keys, 3))
+	assert.False(t, rejections.IsRejected("release", "04", keys, 3))
+	assert.False(t, rejections.HasRejected("cc", "release", "04"))
+
+	// TEST 4: safe txs and burns of rejected mint entries
+	assert.Equal(t, map[string]bool{"0xab": true}, rejections.SafeTxHashes("CC"))
+	assert.Empty(t, rejections.SafeTxHashes("aa"))
+	assert.Equal(t, map[string]bool{"acc://ab@bridge.acme/1-tkn": true}, rejections.BurnTxHashes("cc"))
+	assert.Empty(t, rejections.BurnTxHashes("aa"))
+
+	// TEST 5: no rejections
+	assert.False(t, Rejections{}.IsRejected("release", "01", keys, 3))
+	assert.False(t, Rejections([]*schema.Rejection{}).HasRejected("aa", "release", "01"))
+
+}
+
+func TestRejectionCache(t *testing.T) {
+
+	cache := RejectionCache{}
+	cache.Add(&schema.Rejection{Queue: "release", EntryHash: "01", Node: "aa"})
+	cache.Add(&schema.Rejection{Queue: "release", EntryHash: "01", Node: "bb"})
+	cache.Add(&schema.Rejection{Queue: "release", EntryHash: "02", Node: "aa"})
+	cache.Add(&schema.Rejection{Queue: "mint", EntryHash: "01", Node: "cc"})
+
+	// TEST 1: rejections are grouped by entry
+	assert.Len(t, cache, 3)
+	assert.Len(t, cache.All(), 4)
+	assert.True(t, cache.All().IsRejected("release", "01", []string{"aa", "bb", "cc"}, 2))
+
+	// TEST 2: rejections of settled entries are dropped
+	var checked []int
+	err := cache.Prune(func(rejections Rejections) (bool, error) {
+		checked = append(checked, len(rejections))
+		return rejections[0].Queue == "release" && rejections[0].EntryHash == "01", nil
+	})
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []int{2, 1, 1}, checked)
+	assert.Len(t, cache, 2)
+	assert.False(t, cache.All().HasRejected("aa", "release", "01"))
+	assert.True(t, cache.All().HasRejected("aa", "release", "02"))
+	assert.True(t, cache.All().HasRejected("cc", "mint", "01"))
+
+	// TEST 3: entries are kept if they can not be checked
+	err = cache.Prune(func(rejections Rejections) (bool, error) {
+		return false, errors.New("api error")
+	})
+	assert.Error(t, err)
+	assert.Len(t, cache, 2)
+
+}
+
+func TestSignedRejection(t *testing.T) {
+
+	key := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+	other := ed25519.NewKeyFromSeed(append(make([]byte, ed25519.SeedSize-1), 1))
+
+	rejection := &schema.Rejection{Queue: "release", EntryHash: "01", Reason: "invalid entry"}
+
+	// TEST 1: signed rejection is verified, the node is set from the key
+	assert.NoError(t, rejection.Sign("acc://bridge.acme", key))
+	assert.NoError(t, rejection.Verify("acc://bridge.acme"))
+	assert.Len(t, rejection.Node, 64)
+
+	// TEST 2: signature of another bridge ADI
+	assert.Error(t, rejection.Verify("acc://other.acme"))
+
+	// TEST 3: rejection on behalf of another node
+	forged := *rejection
+	assert.NoError(t, forged.Sign("acc://bridge.acme", other))
+	forged.Node = rejection.Node
+	assert.Error(t, forged.Verify("acc://bridge.acme"))
+
+	// TEST 4: modified rejection
+	modified := *rejection
+	modified.EntryHash = "02"
+	assert.Error(t, modified.Verify("acc://bridge.acme"))
+
+	// TEST 5: unsigned rejection
+	assert.Error(t, (&schema.Rejection{Queue: "release", EntryHash: "01", Node: rejection.Node}).Verify("acc://bridge.acme"))
+
+}
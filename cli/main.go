@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -19,6 +20,7 @@ import (
 	"github.com/AccumulateNetwork/bridge/evm"
 	"github.com/AccumulateNetwork/bridge/gnosis"
 	"github.com/AccumulateNetwork/bridge/schema"
+	"github.com/AccumulateNetwork/bridge/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/urfave/cli/v2"
@@ -80,41 +82,19 @@ func main() {
 						return err
 					}
 
-					safe, err := g.GetSafe()
+					safe, err := g.GetSafeState()
 					if err != nil {
 						fmt.Print("can not get gnosis safe: ")
 						return err
 					}
 
-					nonce, err := strconv.ParseInt(safe.Nonce, 10, 64)
-					if err != nil {
-						fmt.Println("[can not parse int from nonce string:", err)
-						return err
-					}
-
-					data, err := abiutil.GenerateMintTxData(token, recipient, big.NewInt(amount))
+					safeTx, err := g.NewMintTx(token, recipient, big.NewInt(amount), safe.Nonce)
 					if err != nil {
 						fmt.Print("can not generate mint tx: ")
 						return err
 					}
 
-					contractHash, signature, err := g.SignMintTx(token, recipient, big.NewInt(amount))
-					if err != nil {
-						fmt.Print("can not sign mint tx: ")
-						return err
-					}
-
-					tx := gnosis.NewMultisigTx{}
-					tx.To = g.BridgeAddress
-					tx.Data = hexutil.Encode(data)
-					tx.GasToken = abiutil.ZERO_ADDR
-					tx.RefundReceiver = abiutil.ZERO_ADDR
-					tx.Nonce = nonce
-					tx.ContractTransactionHash = hexutil.Encode(contractHash)
-					tx.Sender = g.PublicKey.Hex()
-					tx.Signature = hexutil.Encode(signature)
-
-					err = g.CreateSafeMultisigTx(&tx)
+					_, err = g.ProposeSafeTx(safeTx, "")
 					if err != nil {
 						fmt.Print("gnosis safe api error: ")
 						return err
@@ -202,29 +182,33 @@ func main() {
 						return fmt.Errorf("tx is already executed")
 					}
 
-					sort.Slice(gnosisTx.Confirmations, func(i, j int) bool {
-						switch strings.Compare(strings.ToLower(gnosisTx.Confirmations[i].Owner), strings.ToLower(gnosisTx.Confirmations[j].Owner)) {
-						case -1:
-							return true
-						case 1:
-							return false
-						}
-						return strings.ToLower(gnosisTx.Confirmations[i].Owner) > strings.ToLower(gnosisTx.Confirmations[j].Owner)
-					})
+					safe, err := g.GetSafeState()
+					if err != nil {
+						fmt.Print("can not get gnosis safe: ")
+						return err
+					}
 
-					// concatenate signatures
-					var sig []byte
-					for _, con := range gnosisTx.Confirmations {
-						sigBytes, err := hexutil.Decode(con.Signature)
-						if err != nil {
-							fmt.Print("can not decode signature hex: ")
-							return err
-						}
-						sig = append(sig, sigBytes...)
+					// recover signers, invalid and non-owner signatures are dropped
+					sigs, err := g.VerifyConfirmations(safe, gnosisTx)
+					if err != nil {
+						fmt.Print("can not verify signatures: ")
+						return err
+					}
+
+					if len(sigs) < int(safe.Threshold) {
+						return fmt.Errorf("%d valid signatures, %d required", len(sigs), safe.Threshold)
+					}
+
+					sig := gnosis.EncodeSignatures(sigs[:safe.Threshold])
+
+					safeTx, err := g.SafeTxFromMultisigTx(gnosisTx)
+					if err != nil {
+						fmt.Print("invalid safe tx: ")
+						return err
 					}
 
 					// generate tx input data
-					txData, err := abiutil.GenerateExecTransaction(g.BridgeAddress, gnosisTx.Data, hexutil.Encode(sig))
+					txData, err := safeTx.EncodeExec(sig)
 					if err != nil {
 						fmt.Print("can not generate tx data: ")
 						return err
@@ -232,6 +216,15 @@ func main() {
 
 					to := common.HexToAddress(g.SafeAddress)
 
+					// simulate execTransaction at the latest block, do not broadcast txs that would fail
+					out, err := cl.Simulate(&to, 0, txData)
+					if err == nil {
+						err = gnosis.CheckExecResult(out)
+					}
+					if err != nil {
+						return fmt.Errorf("simulation failed: %s", gnosis.DescribeRevert(err))
+					}
+
 					// submit ethereum tx
 					sentTx, err := cl.Submit(gasPrice, priorityFee, &to, 0, txData)
 					if err != nil {
@@ -244,6 +237,479 @@ func main() {
 
 				},
 			},
+			{
+				Name:  "token-admin",
+				Usage: "Generates, signs and proposes gnosis safe tx for wrapped token administration",
+				Action: func(c *cli.Context) error {
+
+					if c.NArg() < 2 {
+						printTokenAdminHelp()
+						return nil
+					}
+
+					action := &gnosis.AdminAction{}
+					action.Action = c.Args().Get(0)
+					action.Token = c.Args().Get(1)
+					action.NewOwner = c.Args().Get(2)
+
+					var conf *config.Config
+					var err error
+					configFile := c.String("config")
+
+					if configFile == "" {
+						usr, err := user.Current()
+						if err != nil {
+							return err
+						}
+						configFile = usr.HomeDir + "/.accumulatebridge/config.yaml"
+					}
+
+					fmt.Printf("using config: %s\n", configFile)
+
+					if conf, err = config.NewConfig(configFile); err != nil {
+						fmt.Print("can not load config: ")
+						return err
+					}
+
+					g, err := gnosis.NewGnosis(conf)
+					if err != nil {
+						fmt.Print("can not init gnosis module: ")
+						return err
+					}
+
+					safe, err := g.GetSafeState()
+					if err != nil {
+						fmt.Print("can not get gnosis safe: ")
+						return err
+					}
+
+					// admin txs are proposed after signed txs, signatures may be shared via accumulate
+					var a *accumulate.AccumulateClient
+					if g.UsesAccumulate() {
+						a, err = accumulate.NewAccumulateClient(conf)
+						if err != nil {
+							fmt.Print("can not init accumulate client: ")
+							return err
+						}
+					}
+
+					signed, err := utils.GetSignedSafeTxs(a, g, safe)
+					if err != nil {
+						fmt.Print("can not get signed safe txs: ")
+						return err
+					}
+
+					txs, err := g.ProposeAdminTx(action, safe, signed)
+					for _, tx := range txs {
+						fmt.Printf("proposed nonce %d, safetxhash: %s\n", tx.Nonce, tx.ContractTransactionHash)
+					}
+					if err != nil {
+						fmt.Print("can not propose admin tx: ")
+						return err
+					}
+
+					fmt.Printf("proposed %s in %d safe txs", action, len(txs))
+
+					return nil
+
+				},
+			},
+			{
+				Name:  "admin-sign",
+				Usage: "Co-signs proposed gnosis safe tx for wrapped token administration",
+				Action: func(c *cli.Context) error {
+
+					if c.NArg() != 1 {
+						printAdminSignHelp()
+						return nil
+					}
+
+					safeTxHash := c.Args().Get(0)
+
+					var conf *config.Config
+					var err error
+					configFile := c.String("config")
+
+					if configFile == "" {
+						usr, err := user.Current()
+						if err != nil {
+							return err
+						}
+						configFile = usr.HomeDir + "/.accumulatebridge/config.yaml"
+					}
+
+					fmt.Printf("using config: %s\n", configFile)
+
+					if conf, err = config.NewConfig(configFile); err != nil {
+						fmt.Print("can not load config: ")
+						return err
+					}
+
+					g, err := gnosis.NewGnosis(conf)
+					if err != nil {
+						fmt.Print("can not init gnosis module: ")
+						return err
+					}
+
+					gnosisTx, err := g.GetSafeMultisigTx(safeTxHash)
+					if err != nil {
+						fmt.Printf("can not get gnosis safe tx with hash %s: ", safeTxHash)
+						return err
+					}
+
+					if gnosisTx.IsExecuted {
+						return fmt.Errorf("tx is already executed")
+					}
+
+					action, err := g.ConfirmAdminTx(gnosisTx)
+					if err != nil {
+						fmt.Print("can not sign admin tx: ")
+						return err
+					}
+
+					fmt.Printf("signed %s, nonce %d", action, gnosisTx.Nonce)
+
+					return nil
+
+				},
+			},
+			{
+				Name:  "safe-admin",
+				Usage: "Generates, signs and proposes gnosis safe tx, changing safe owners or threshold",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "yes", Usage: "sign without confirmation"},
+				},
+				Action: func(c *cli.Context) error {
+
+					if c.NArg() < 2 {
+						printSafeAdminHelp()
+						return nil
+					}
+
+					action, err := gnosis.ParseOwnerAction(c.Args().Get(0), c.Args().Slice()[1:])
+					if err != nil {
+						printSafeAdminHelp()
+						return err
+					}
+
+					var conf *config.Config
+					configFile := c.String("config")
+
+					if configFile == "" {
+						usr, err := user.Current()
+						if err != nil {
+							return err
+						}
+						configFile = usr.HomeDir + "/.accumulatebridge/config.yaml"
+					}
+
+					fmt.Printf("using config: %s\n", configFile)
+
+					if conf, err = config.NewConfig(configFile); err != nil {
+						fmt.Print("can not load config: ")
+						return err
+					}
+
+					g, err := gnosis.NewGnosis(conf)
+					if err != nil {
+						fmt.Print("can not init gnosis module: ")
+						return err
+					}
+
+					safe, err := g.GetSafeState()
+					if err != nil {
+						fmt.Print("can not get gnosis safe: ")
+						return err
+					}
+
+					safeTx, change, err := g.PrepareOwnerTx(safe, action)
+					if err != nil {
+						fmt.Print("can not generate owner tx: ")
+						return err
+					}
+
+					fmt.Printf("proposal: %s, nonce %d\n%s", action, safeTx.Nonce, change)
+
+					if !c.Bool("yes") && !confirm() {
+						return fmt.Errorf("cancelled")
+					}
+
+					signed, err := g.ProposeSafeTx(safeTx, "")
+					if err != nil {
+						fmt.Print("can not propose owner tx: ")
+						return err
+					}
+
+					fmt.Printf("proposed %s, safetxhash: %s", action, signed.ContractTransactionHash)
+
+					return nil
+
+				},
+			},
+			{
+				Name:  "safe-admin-sign",
+				Usage: "Co-signs proposed gnosis safe tx, changing safe owners or threshold",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{Name: "yes", Usage: "sign without confirmation"},
+				},
+				Action: func(c *cli.Context) error {
+
+					if c.NArg() != 1 {
+						printSafeAdminSignHelp()
+						return nil
+					}
+
+					safeTxHash := c.Args().Get(0)
+
+					var conf *config.Config
+					var err error
+					configFile := c.String("config")
+
+					if configFile == "" {
+						usr, err := user.Current()
+						if err != nil {
+							return err
+						}
+						configFile = usr.HomeDir + "/.accumulatebridge/config.yaml"
+					}
+
+					fmt.Printf("using config: %s\n", configFile)
+
+					if conf, err = config.NewConfig(configFile); err != nil {
+						fmt.Print("can not load config: ")
+						return err
+					}
+
+					g, err := gnosis.NewGnosis(conf)
+					if err != nil {
+						fmt.Print("can not init gnosis module: ")
+						return err
+					}
+
+					safe, err := g.GetSafeState()
+					if err != nil {
+						fmt.Print("can not get gnosis safe: ")
+						return err
+					}
+
+					gnosisTx, err := g.GetSafeMultisigTx(safeTxHash)
+					if err != nil {
+						fmt.Printf("can not get gnosis safe tx with hash %s: ", safeTxHash)
+						return err
+					}
+
+					if gnosisTx.IsExecuted {
+						return fmt.Errorf("tx is already executed")
+					}
+
+					// owners and threshold are checked against the current safe state
+					action, change, err := g.DecodeOwnerTx(safe, gnosisTx)
+					if err != nil {
+						fmt.Print("can not decode owner tx: ")
+						return err
+					}
+
+					fmt.Printf("proposal: %s, nonce %d\n%s", action, gnosisTx.Nonce, change)
+
+					if !c.Bool("yes") && !confirm() {
+						return fmt.Errorf("cancelled")
+					}
+
+					_, err = g.ConfirmOwnerTx(safe, gnosisTx)
+					if err != nil {
+						fmt.Print("can not sign owner tx: ")
+						return err
+					}
+
+					fmt.Printf("signed %s, nonce %d", action, gnosisTx.Nonce)
+
+					return nil
+
+				},
+			},
+			{
+				Name:  "cancel",
+				Usage: "Generates, signs and shares gnosis safe tx, cancelling pending txs with the nonce",
+				Action: func(c *cli.Context) error {
+
+					if c.NArg() != 1 {
+						printCancelHelp()
+						return nil
+					}
+
+					nonce, err := strconv.ParseInt(c.Args().Get(0), 10, 64)
+					if err != nil {
+						fmt.Print("incorrect nonce: ")
+						return err
+					}
+
+					var conf *config.Config
+					configFile := c.String("config")
+
+					if configFile == "" {
+						usr, err := user.Current()
+						if err != nil {
+							return err
+						}
+						configFile = usr.HomeDir + "/.accumulatebridge/config.yaml"
+					}
+
+					fmt.Printf("using config: %s\n", configFile)
+
+					if conf, err = config.NewConfig(configFile); err != nil {
+						fmt.Print("can not load config: ")
+						return err
+					}
+
+					g, err := gnosis.NewGnosis(conf)
+					if err != nil {
+						fmt.Print("can not init gnosis module: ")
+						return err
+					}
+
+					safe, safeTx, err := g.SignCancelTx(nonce)
+					if err != nil {
+						fmt.Print("can not sign cancel tx: ")
+						return err
+					}
+
+					// signature is shared via configured signature transport, like mint signatures of the bridge node
+					var a *accumulate.AccumulateClient
+					if g.UsesAccumulate() {
+						a, err = accumulate.NewAccumulateClient(conf)
+						if err != nil {
+							fmt.Print("can not init accumulate client: ")
+							return err
+						}
+					}
+
+					err = utils.ShareSignature(a, g, safe, safeTx, "", 0)
+					if err != nil {
+						fmt.Print("can not propose cancel tx: ")
+						return err
+					}
+
+					fmt.Printf("signed cancellation of nonce %d, safetxhash: %s", nonce, safeTx.ContractTransactionHash)
+
+					return nil
+
+				},
+			},
+			{
+				Name:  "refund",
+				Usage: "Generates, signs and shares gnosis safe tx, returning lock transfer without valid destination to the sender",
+				Action: func(c *cli.Context) error {
+
+					if c.NArg() != 3 {
+						printRefundHelp()
+						return nil
+					}
+
+					txidBytes, err := hexutil.Decode(c.Args().Get(0))
+					if err != nil || len(txidBytes) != common.HashLength {
+						return fmt.Errorf("incorrect evm txid %s", c.Args().Get(0))
+					}
+					txid := common.BytesToHash(txidBytes)
+
+					logIndex, err := strconv.ParseUint(c.Args().Get(1), 10, 32)
+					if err != nil {
+						fmt.Print("incorrect log index: ")
+						return err
+					}
+
+					nonce, err := strconv.ParseInt(c.Args().Get(2), 10, 64)
+					if err != nil {
+						fmt.Print("incorrect nonce: ")
+						return err
+					}
+
+					var conf *config.Config
+					configFile := c.String("config")
+
+					if configFile == "" {
+						usr, err := user.Current()
+						if err != nil {
+							return err
+						}
+						configFile = usr.HomeDir + "/.accumulatebridge/config.yaml"
+					}
+
+					fmt.Printf("using config: %s\n", configFile)
+
+					if conf, err = config.NewConfig(configFile); err != nil {
+						fmt.Print("can not load config: ")
+						return err
+					}
+
+					g, err := gnosis.NewGnosis(conf)
+					if err != nil {
+						fmt.Print("can not init gnosis module: ")
+						return err
+					}
+
+					e, err := evm.NewEVMClient(conf)
+					if err != nil {
+						fmt.Print("can not init evm client: ")
+						return err
+					}
+
+					a, err := accumulate.NewAccumulateClient(conf)
+					if err != nil {
+						fmt.Print("can not init accumulate client: ")
+						return err
+					}
+
+					lock, err := e.GetLockLog(g.SafeAddress, txid, uint(logIndex))
+					if err != nil {
+						fmt.Print("can not get lock transfer: ")
+						return err
+					}
+
+					// only transfers recorded as exceptions are refunded, tokens of other transfers were issued on accumulate
+					releaseQueue := accumulate.GenerateReleaseDataAccount(a.ADI, int64(e.ChainId), accumulate.ACC_RELEASE_QUEUE)
+
+					entry, err := utils.FindReleaseEntry(a, releaseQueue, lock)
+					if err != nil {
+						fmt.Print("can not find release entry: ")
+						return err
+					}
+
+					err = utils.ValidateRefund(entry, lock)
+					if err != nil {
+						return err
+					}
+
+					// refunds executed at other nonces are found by the lock reference in the tx input
+					refunds, err := e.FindRefunds(g.SafeAddress, lock, abiutil.RefundReference(txid, uint(logIndex)))
+					if err != nil {
+						fmt.Print("can not check previous refunds: ")
+						return err
+					}
+
+					if len(refunds) > 0 {
+						return fmt.Errorf("lock transfer is already refunded by tx %s", refunds[0].TxID.Hex())
+					}
+
+					fmt.Printf("refunding %s of token %s to %s, release exception: %s\n", lock.Amount, lock.Token.Hex(), lock.From.Hex(), entry.Exception)
+
+					safe, safeTx, err := g.SignRefundTx(lock.Token.Hex(), lock.From.Hex(), lock.Amount, txid, uint(logIndex), nonce)
+					if err != nil {
+						fmt.Print("can not sign refund tx: ")
+						return err
+					}
+
+					err = utils.ShareSignature(a, g, safe, safeTx, "", 0)
+					if err != nil {
+						fmt.Print("can not propose refund tx: ")
+						return err
+					}
+
+					fmt.Printf("signed refund at nonce %d, safetxhash: %s", nonce, safeTx.ContractTransactionHash)
+
+					return nil
+
+				},
+			},
 			{
 				Name:  "release",
 				Usage: "Generates and submits tx to release native tokens",
@@ -286,7 +752,7 @@ func main() {
 						return err
 					}
 
-					txhash, err := a.SendTokens(to, amount, token, int64(conf.EVM.ChainId))
+					txhash, err := a.SendTokens(to, amount, token, int64(conf.EVM.ChainId), "")
 					if err != nil {
 						fmt.Print("tx failed: ")
 						return err
@@ -354,6 +820,7 @@ func main() {
 				Usage: "Generates and submits accumulate data entry for token register",
 				Flags: []cli.Flag{
 					&cli.BoolFlag{Name: "disable"},
+					&cli.StringFlag{Name: "mode", Value: schema.TOKEN_MODE_MINT},
 				},
 				Action: func(c *cli.Context) error {
 
@@ -400,6 +867,16 @@ func main() {
 						token.Enabled = false
 					}
 
+					// parse --mode flag: mint for accumulate-native tokens, lock for EVM-native tokens
+					mode := c.String("mode")
+					if mode != schema.TOKEN_MODE_MINT && mode != schema.TOKEN_MODE_LOCK {
+						printTokenRegisterHelp()
+						return fmt.Errorf("mode must be %s or %s", schema.TOKEN_MODE_MINT, schema.TOKEN_MODE_LOCK)
+					}
+					if mode == schema.TOKEN_MODE_LOCK {
+						token.Mode = mode
+					}
+
 					for i := 1; i <= len(wrapped)-1; i++ {
 
 						wrappedToken := &schema.WrappedToken{}
@@ -852,6 +1329,45 @@ func printEthSubmitHelp() {
 	fmt.Println("eth-submit [gnosis safetxhash] [max gas fee (optional)] [max priority fee (optional)]")
 }
 
+func printTokenAdminHelp() {
+	fmt.Println("token-admin [action] [token address] [new owner (for ownership transfers)]")
+	fmt.Println("actions:")
+	fmt.Println("  pause, unpause: token calls, for tokens owned by the bridge 3 txs are proposed: transfer-token-ownership to gnosis safe, the call, transfer-ownership back to the bridge")
+	fmt.Println("  transfer-ownership: token call for tokens owned by gnosis safe, transfer-token-ownership for tokens owned by the bridge")
+	fmt.Println("  transfer-token-ownership, renounce-token-ownership: bridge calls for tokens owned by the bridge")
+	fmt.Println("txs are proposed at the nonces after signed txs, which are not executed yet")
+	fmt.Println("every proposed tx is co-signed with admin-sign and executed with eth-submit or by the leader, in order of nonces")
+}
+
+func printAdminSignHelp() {
+	fmt.Println("admin-sign [gnosis safetxhash]")
+}
+
+func printSafeAdminHelp() {
+	fmt.Println("safe-admin [action] [args]")
+	fmt.Println("actions:")
+	fmt.Println("  add-owner [owner] [threshold]")
+	fmt.Println("  remove-owner [owner] [threshold]")
+	fmt.Println("  swap-owner [owner] [new owner]")
+	fmt.Println("  change-threshold [threshold]")
+	fmt.Println("proposed tx is co-signed with safe-admin-sign and executed with eth-submit")
+}
+
+func printSafeAdminSignHelp() {
+	fmt.Println("safe-admin-sign [gnosis safetxhash]")
+}
+
+func printCancelHelp() {
+	fmt.Println("cancel [gnosis safe nonce]")
+	fmt.Println("every signer runs the same command, signatures are shared via signaturetransport, cancellation is executed with eth-submit or by the leader")
+}
+
+func printRefundHelp() {
+	fmt.Println("refund [evm txid] [log index] [gnosis safe nonce]")
+	fmt.Println("returns tokens of lock transfer, recorded in the release queue as exception (e.g. no destination), to the sender")
+	fmt.Println("every signer runs the same command with the same nonce, signatures are shared via signaturetransport, refund is executed with eth-submit or by the leader")
+}
+
 func printReleaseHelp() {
 	fmt.Println("release [token] [recipient] [amount]")
 }
@@ -861,7 +1377,7 @@ func printAccSignHelp() {
 }
 
 func printTokenRegisterHelp() {
-	fmt.Println("token-register [accumulate token URL] '{\"address\":\"\",\"chainId\":\"\",\"mintTxCost\":\"\"}'... [--disable (optional)]")
+	fmt.Println("token-register [accumulate token URL] '{\"address\":\"\",\"chainId\":\"\",\"mintTxCost\":\"\"}'... [--disable (optional)] [--mode mint|lock (optional)]")
 }
 
 func printUpdateFeesHelp() {
@@ -879,3 +1395,19 @@ func printSetMintHeightHelp() {
 func printSetLeaderHelp() {
 	fmt.Println("set-leader [public key hash]")
 }
+
+// confirm asks user to confirm signing
+func confirm() bool {
+
+	fmt.Print("sign? [y/N]: ")
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+
+	return answer == "y" || answer == "yes"
+
+}
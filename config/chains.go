@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"github.com/go-yaml/yaml"
+)
+
+// ChainProfile describes capabilities of EVM chain
+type ChainProfile struct {
+	ChainId    int    `yaml:"chainId" json:"chainId"`
+	Name       string `yaml:"name" json:"name"`
+	EIP1559    bool   `yaml:"eip1559" json:"eip1559"`       // if false, legacy txs are used
+	GasLimit   uint64 `yaml:"gasLimit" json:"gasLimit"`     // gas limit ceiling for estimated txs
+	SafeAPI    string `yaml:"safeAPI" json:"safeAPI"`       // Safe Transaction Service URL
+	SafeAPIKey string `yaml:"safeAPIKey" json:"safeAPIKey"` // (optional) Safe Transaction Service API key
+}
+
+// DefaultChainProfiles are built-in chain profiles, that can be extended or overridden by chain profiles file
+var DefaultChainProfiles = map[int]*ChainProfile{
+	1:     {ChainId: 1, Name: "mainnet", EIP1559: true, GasLimit: 200000, SafeAPI: "https://safe-transaction-mainnet.safe.global/api/v1/"},
+	5:     {ChainId: 5, Name: "goerli", EIP1559: true, GasLimit: 200000, SafeAPI: "https://safe-transaction-goerli.safe.global/api/v1/"},
+	56:    {ChainId: 56, Name: "bsc", EIP1559: false, GasLimit: 200000, SafeAPI: "https://safe-transaction-bsc.safe.global/api/v1/"},
+	8453:  {ChainId: 8453, Name: "base", EIP1559: true, GasLimit: 200000, SafeAPI: "https://safe-transaction-base.safe.global/api/v1/"},
+	42161: {ChainId: 42161, Name: "arbitrum", EIP1559: true, GasLimit: 5000000, SafeAPI: "https://safe-transaction-arbitrum.safe.global/api/v1/"},
+}
+
+// LoadChainProfiles reads chain profiles from YAML file on top of default profiles
+func LoadChainProfiles(profilesFile string) (map[int]*ChainProfile, error) {
+
+	profiles := make(map[int]*ChainProfile)
+	for chainId, profile := range DefaultChainProfiles {
+		p := *profile
+		profiles[chainId] = &p
+	}
+
+	if profilesFile == "" {
+		return profiles, nil
+	}
+
+	profilesBytes, err := ioutil.ReadFile(profilesFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var fileProfiles []*ChainProfile
+	err = yaml.Unmarshal(profilesBytes, &fileProfiles)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, profile := range fileProfiles {
+		if profile.ChainId <= 0 {
+			return nil, fmt.Errorf("invalid chainId %d in chain profiles file %s", profile.ChainId, profilesFile)
+		}
+		if profile.GasLimit == 0 {
+			return nil, fmt.Errorf("gasLimit is required for chainId %d in chain profiles file %s", profile.ChainId, profilesFile)
+		}
+		profiles[profile.ChainId] = profile
+	}
+
+	return profiles, nil
+
+}
+
+// GetChainProfile returns profile of the chain from config
+func (c *Config) GetChainProfile() (*ChainProfile, error) {
+
+	profiles, err := LoadChainProfiles(c.EVM.ChainProfiles)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, ok := profiles[c.EVM.ChainId]
+	if !ok {
+		return nil, fmt.Errorf("received unknown chainId from config: %d", c.EVM.ChainId)
+	}
+
+	// Safe Transaction Service endpoint and credentials from config take precedence over the profile
+	if c.EVM.SafeAPI != "" {
+		profile.SafeAPI = c.EVM.SafeAPI
+	}
+	if c.EVM.SafeAPIKey != "" {
+		profile.SafeAPIKey = c.EVM.SafeAPIKey
+	}
+
+	return profile, nil
+
+}
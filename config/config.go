@@ -20,15 +20,37 @@ type Config struct {
 		BridgeADI  string `required:"true" default:"" json:"bridgeADI" form:"bridgeADI" query:"bridgeADI"`
 		KeyBook    string `required:"true" default:"book" json:"keyBook" form:"keyBook" query:"keyBook"`
 		PrivateKey string `required:"true" default:"" json:"privateKey" form:"privateKey" query:"privateKey"`
+		// key page credits monitoring
+		MinCredits float64 `required:"false" default:"100" json:"minCredits" form:"minCredits" query:"minCredits"`
+		// (optional) ACME token account to refill the key page from, and ACME amount to spend
+		CreditsTopUpAccount string  `required:"false" default:"" json:"creditsTopUpAccount" form:"creditsTopUpAccount" query:"creditsTopUpAccount"`
+		CreditsTopUpAmount  float64 `required:"false" default:"0" json:"creditsTopUpAmount" form:"creditsTopUpAmount" query:"creditsTopUpAmount"`
+		// (optional) key page of the bridge key book to publish safe tx signatures with, main key page if empty
+		SignaturesKeyPage string `required:"false" default:"" json:"signaturesKeyPage" form:"signaturesKeyPage" query:"signaturesKeyPage"`
+		// (optional) minutes without heartbeats after which the leader is replaced by the next key page key, 0 = failover disabled
+		LeaderTimeout int `required:"false" default:"0" json:"leaderTimeout" form:"leaderTimeout" query:"leaderTimeout"`
+		// (optional) minutes after which auditors report valid deposits and burns, not queued by the leader, 0 = disabled
+		CensorshipWindow int `required:"false" default:"0" json:"censorshipWindow" form:"censorshipWindow" query:"censorshipWindow"`
 	}
 	EVM struct {
-		Node           string  `required:"false" default:"" json:"node" form:"node" query:"node"`
-		ChainId        int     `required:"true" default:"1" json:"chainId" form:"chainId" query:"chainId"`
-		SafeAddress    string  `required:"true" default:"" json:"safeAddress" form:"safeAddress" query:"safeAddress"`
-		BridgeAddress  string  `required:"true" default:"" json:"bridgeAddress" form:"bridgeAddress" query:"bridgeAddress"`
-		PrivateKey     string  `required:"true" default:"" json:"privateKey" form:"privateKey" query:"privateKey"`
-		MaxGasFee      float64 `required:"true" default:"30" json:"maxGasFee" form:"maxGasFee" query:"maxGasFee"`
-		MaxPriorityFee float64 `required:"true" default:"2" json:"maxPriorityFee" form:"maxPriorityFee" query:"maxPriorityFee"`
+		Node           string   `required:"false" default:"" json:"node" form:"node" query:"node"`
+		Nodes          []string `required:"false" json:"nodes" form:"nodes" query:"nodes"`
+		Quorum         int      `required:"false" default:"1" json:"quorum" form:"quorum" query:"quorum"`
+		ChainId        int      `required:"true" default:"1" json:"chainId" form:"chainId" query:"chainId"`
+		SafeAddress    string   `required:"true" default:"" json:"safeAddress" form:"safeAddress" query:"safeAddress"`
+		BridgeAddress  string   `required:"true" default:"" json:"bridgeAddress" form:"bridgeAddress" query:"bridgeAddress"`
+		PrivateKey     string   `required:"true" default:"" json:"privateKey" form:"privateKey" query:"privateKey"`
+		MaxGasFee      float64  `required:"true" default:"30" json:"maxGasFee" form:"maxGasFee" query:"maxGasFee"`
+		MaxPriorityFee float64  `required:"true" default:"2" json:"maxPriorityFee" form:"maxPriorityFee" query:"maxPriorityFee"`
+		GasMultiplier  float64  `required:"false" default:"1.2" json:"gasMultiplier" form:"gasMultiplier" query:"gasMultiplier"`
+		StuckTxTimeout int      `required:"false" default:"5" json:"stuckTxTimeout" form:"stuckTxTimeout" query:"stuckTxTimeout"`
+		LogRange       int      `required:"false" default:"2000" json:"logRange" form:"logRange" query:"logRange"`
+		ChainProfiles  string   `required:"false" default:"" json:"chainProfiles" form:"chainProfiles" query:"chainProfiles"`
+		// (optional) Safe Transaction Service URL and API key, override chain profile
+		SafeAPI    string `required:"false" default:"" json:"safeAPI" form:"safeAPI" query:"safeAPI"`
+		SafeAPIKey string `required:"false" default:"" json:"safeAPIKey" form:"safeAPIKey" query:"safeAPIKey"`
+		// (optional) where safe tx signatures are exchanged: safe (Safe Transaction Service), accumulate (signatures data account) or both
+		SignatureTransport string `required:"false" default:"safe" json:"signatureTransport" form:"signatureTransport" query:"signatureTransport"`
 	}
 }
 
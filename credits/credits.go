@@ -0,0 +1,55 @@
+package credits
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// credits monitor decisions
+const (
+	CREDITS_OK       = "ok"       // balance is not below the minimum
+	CREDITS_LOW      = "low"      // balance is below the minimum, the key page is not refilled by this node now
+	CREDITS_TOP_UP   = "top-up"   // balance is below the minimum, the key page is refilled
+	CREDITS_DISABLED = "disabled" // balance is below the minimum, automatic top up is not configured
+)
+
+// ErrInvalidTopUp is returned if top up account is set, but top up amount is not positive
+var ErrInvalidTopUp = errors.New("credits top up amount should be positive")
+
+// Policy is the minimum credit balance of the key page and its automatic top up
+type Policy struct {
+	MinCredits   float64       // credits
+	TopUpAccount string        // ACME token account the key page is refilled from, empty to disable top up
+	TopUpAmount  float64       // ACME spent on every top up
+	Cooldown     time.Duration // time for the previous top up to be applied before sending a new one
+}
+
+// Decide returns what the monitor does with the balance. Only the leader refills the key page to avoid duplicate
+// top ups by every node, failed top ups are not counted in lastTopUp and are retried on the next check
+func (p *Policy) Decide(balance float64, isLeader bool, lastTopUp time.Time, now time.Time) (string, error) {
+
+	if balance >= p.MinCredits {
+		return CREDITS_OK, nil
+	}
+
+	if p.TopUpAccount == "" {
+		return CREDITS_DISABLED, nil
+	}
+
+	if p.TopUpAmount <= 0 {
+		return CREDITS_DISABLED, ErrInvalidTopUp
+	}
+
+	if !isLeader || now.Sub(lastTopUp) < p.Cooldown {
+		return CREDITS_LOW, nil
+	}
+
+	return CREDITS_TOP_UP, nil
+
+}
+
+// Amount returns top up amount in ACME base units
+func (p *Policy) Amount(precision int) int64 {
+	return int64(math.Round(p.TopUpAmount * math.Pow10(precision)))
+}
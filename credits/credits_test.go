@@ -0,0 +1,62 @@
+package credits
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecide(t *testing.T) {
+
+	now := time.Unix(1700000000, 0)
+
+	policy := &Policy{MinCredits: 100, TopUpAccount: "acc://bridge.acme/ACME", TopUpAmount: 5, Cooldown: 10 * time.Minute}
+
+	cases := []struct {
+		name      string
+		policy    *Policy
+		balance   float64
+		isLeader  bool
+		lastTopUp time.Time
+		decision  string
+		err       error
+	}{
+		{"balance above minimum", policy, 150, true, time.Time{}, CREDITS_OK, nil},
+		{"balance equals minimum", policy, 100, true, time.Time{}, CREDITS_OK, nil},
+		{"balance below minimum", policy, 99.99, true, time.Time{}, CREDITS_TOP_UP, nil},
+		{"auditor does not refill", policy, 50, false, time.Time{}, CREDITS_LOW, nil},
+		{"previous top up is not applied yet", policy, 50, true, now.Add(-5 * time.Minute), CREDITS_LOW, nil},
+		{"cooldown passed", policy, 50, true, now.Add(-10 * time.Minute), CREDITS_TOP_UP, nil},
+		{"top up disabled", &Policy{MinCredits: 100}, 50, true, time.Time{}, CREDITS_DISABLED, nil},
+		{"zero top up amount", &Policy{MinCredits: 100, TopUpAccount: "acc://bridge.acme/ACME"}, 50, true, time.Time{}, CREDITS_DISABLED, ErrInvalidTopUp},
+		{"negative top up amount", &Policy{MinCredits: 100, TopUpAccount: "acc://bridge.acme/ACME", TopUpAmount: -1}, 50, true, time.Time{}, CREDITS_DISABLED, ErrInvalidTopUp},
+		{"invalid top up ignored above minimum", &Policy{MinCredits: 100, TopUpAccount: "acc://bridge.acme/ACME"}, 150, true, time.Time{}, CREDITS_OK, nil},
+	}
+
+	for _, c := range cases {
+		decision, err := c.policy.Decide(c.balance, c.isLeader, c.lastTopUp, now)
+		assert.Equal(t, c.decision, decision, c.name)
+		assert.Equal(t, c.err, err, c.name)
+	}
+
+}
+
+func TestAmount(t *testing.T) {
+
+	cases := []struct {
+		amount    float64
+		precision int
+		expected  int64
+	}{
+		{5, 8, 500000000},
+		{0.1, 8, 10000000},
+		{1.23456789, 8, 123456789},
+		{0, 8, 0},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.expected, (&Policy{TopUpAmount: c.amount}).Amount(c.precision))
+	}
+
+}
@@ -1,7 +1,6 @@
 package evm
 
 import (
-	"context"
 	"encoding/hex"
 	"fmt"
 
@@ -18,10 +17,12 @@ type ERC20 struct {
 }
 
 type Tx struct {
-	TxHash  string
-	ChainId int64
-	To      string
-	Data    string
+	TxHash    string
+	BlockHash string
+	Status    uint64 // receipt status
+	ChainId   int64
+	To        string
+	Data      string
 }
 
 // GetERC20 gets ERC20 Token info
@@ -36,11 +37,6 @@ func (e *EVMClient) GetERC20(tokenAddress string) (*ERC20, error) {
 		return nil, err
 	}
 
-	owner, err := instance.Owner(&bind.CallOpts{})
-	if err != nil {
-		return nil, err
-	}
-
 	symbol, err := instance.Symbol(&bind.CallOpts{})
 	if err != nil {
 		return nil, err
@@ -51,7 +47,12 @@ func (e *EVMClient) GetERC20(tokenAddress string) (*ERC20, error) {
 		return nil, err
 	}
 
-	token.Owner = owner.String()
+	// EVM-native tokens may not implement owner(), owner is empty in this case
+	owner, err := instance.Owner(&bind.CallOpts{})
+	if err == nil {
+		token.Owner = owner.String()
+	}
+
 	token.Symbol = symbol
 	token.Decimals = int64(decimals)
 
@@ -59,23 +60,25 @@ func (e *EVMClient) GetERC20(tokenAddress string) (*ERC20, error) {
 
 }
 
-// GetTx gets tx by hash
+// GetTx gets tx by hash, cross-checked with other providers if quorum is configured
 func (e *EVMClient) GetTx(hash string) (*Tx, error) {
 
 	tx := &Tx{}
 	txid := common.HexToHash(hash)
 
-	evmTx, isPending, err := e.Client.TransactionByHash(context.Background(), txid)
+	evmTx, receipt, err := getTx(e.Client, txid)
 	if err != nil {
 		return nil, err
 	}
 
-	// check if tx is pending
-	if isPending {
-		return nil, fmt.Errorf("tx %s is pending, skipping", evmTx.Hash())
+	err = e.verifyTx(txid, receipt)
+	if err != nil {
+		return nil, err
 	}
 
 	tx.TxHash = evmTx.Hash().Hex()
+	tx.BlockHash = receipt.BlockHash.Hex()
+	tx.Status = receipt.Status
 
 	// check chain id
 	if int64(e.ChainId) != evmTx.ChainId().Int64() {
@@ -91,3 +94,17 @@ func (e *EVMClient) GetTx(hash string) (*Tx, error) {
 	return tx, nil
 
 }
+
+// IsPaused checks if wrapped token is paused
+func (e *EVMClient) IsPaused(tokenAddress string) (bool, error) {
+
+	address := common.HexToAddress(tokenAddress)
+
+	instance, err := binding.NewWrappedToken(address, e.Client)
+	if err != nil {
+		return false, err
+	}
+
+	return instance.Paused(&bind.CallOpts{})
+
+}
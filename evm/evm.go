@@ -4,7 +4,6 @@ import (
 	"context"
 	"crypto/ecdsa"
 	"fmt"
-	"strconv"
 
 	"github.com/AccumulateNetwork/bridge/config"
 	"github.com/ethereum/go-ethereum/common"
@@ -12,14 +11,6 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
-const (
-	GAS_LIMIT_MAINNET   = 200000
-	GAS_LIMIT_GOERLI    = 200000
-	GAS_LIMIT_ARBITRUM  = 5000000
-	GAS_LIMIT_BNB_CHAIN = 200000
-	GAS_LIMIT_BASE      = 200000
-)
-
 type EVMClient struct {
 	API            string
 	ChainId        int
@@ -28,7 +19,12 @@ type EVMClient struct {
 	Client         *ethclient.Client
 	MaxGasFee      float64
 	MaxPriorityFee float64
-	GasLimit       int64
+	GasLimit       int64   // gas limit ceiling from chain profile
+	GasMultiplier  float64 // safety multiplier for estimated gas
+	EIP1559        bool
+	LogRange       int64       // max number of blocks in a single logs query
+	Providers      []*Provider // additional providers to cross-check logs and txs
+	Quorum         int         // number of providers, including the main node, that must return the same data
 }
 
 // NewEVMClient constructs the EVM client
@@ -61,21 +57,47 @@ func NewEVMClient(conf *config.Config) (*EVMClient, error) {
 	c.Client = client
 	c.ChainId = int(chainId.Int64())
 
-	switch c.ChainId {
-
-	case 1:
-		c.GasLimit = GAS_LIMIT_MAINNET
-	case 5:
-		c.GasLimit = GAS_LIMIT_GOERLI
-	case 56:
-		c.GasLimit = GAS_LIMIT_BNB_CHAIN
-	case 8453:
-		c.GasLimit = GAS_LIMIT_BASE
-	case 42161:
-		c.GasLimit = GAS_LIMIT_ARBITRUM
-	default:
-		return nil, fmt.Errorf("received unknown chainId from config: %s", strconv.Itoa(c.ChainId))
+	for _, node := range conf.EVM.Nodes {
+
+		providerClient, err := ethclient.Dial(node)
+		if err != nil {
+			return nil, fmt.Errorf("can not connect to node: %s", node)
+		}
+
+		providerChainId, err := providerClient.ChainID(context.Background())
+		if err != nil {
+			return nil, fmt.Errorf("can not get chainId from node: %s", node)
+		}
+
+		if providerChainId.Cmp(chainId) != 0 {
+			return nil, fmt.Errorf("chainId from node %s is %d, expected %d", node, providerChainId, chainId)
+		}
+
+		c.Providers = append(c.Providers, &Provider{API: node, Client: providerClient})
+
+	}
+
+	c.Quorum = conf.EVM.Quorum
+	if c.Quorum < 1 || c.Quorum > len(c.Providers)+1 {
+		return nil, fmt.Errorf("quorum from config should be between 1 and %d (number of nodes), received %d", len(c.Providers)+1, conf.EVM.Quorum)
+	}
+
+	profile, err := conf.GetChainProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	c.GasLimit = int64(profile.GasLimit)
+	c.EIP1559 = profile.EIP1559
+
+	c.GasMultiplier = conf.EVM.GasMultiplier
+	if c.GasMultiplier < 1 {
+		return nil, fmt.Errorf("gasMultiplier from config should be >= 1, received %.2f", conf.EVM.GasMultiplier)
+	}
 
+	c.LogRange = int64(conf.EVM.LogRange)
+	if c.LogRange < 1 {
+		return nil, fmt.Errorf("logRange from config should be >= 1, received %d", conf.EVM.LogRange)
 	}
 
 	if conf.EVM.PrivateKey == "" {
@@ -0,0 +1,145 @@
+package evm
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+const (
+	FEE_HISTORY_BLOCKS     = 10 // number of latest blocks to derive priority fee from
+	FEE_HISTORY_PERCENTILE = 50 // priority fee percentile of txs in each block
+	BASE_FEE_MULTIPLIER    = 2  // fee cap covers base fee growth for several blocks
+)
+
+// GasPrice is a pricing decision for EVM tx
+type GasPrice struct {
+	BaseFee   *big.Int // next block base fee, nil for legacy txs
+	GasTipCap *big.Int // priority fee, nil for legacy txs
+	GasFeeCap *big.Int // max fee per gas or gas price for legacy txs
+	Capped    bool     // if suggested price was limited by configured maximum
+}
+
+func (p *GasPrice) String() string {
+
+	capped := ""
+	if p.Capped {
+		capped = " (capped by config)"
+	}
+
+	if p.BaseFee == nil {
+		return fmt.Sprintf("gasPrice=%s gwei%s", toGwei(p.GasFeeCap), capped)
+	}
+
+	return fmt.Sprintf("baseFee=%s gwei, tip=%s gwei, feeCap=%s gwei%s", toGwei(p.BaseFee), toGwei(p.GasTipCap), toGwei(p.GasFeeCap), capped)
+
+}
+
+// SuggestEIP1559Price derives tip and fee cap from the node suggestion and fee history, bounded by max values in gwei
+func (e *EVMClient) SuggestEIP1559Price(maxGasFee float64, maxPriorityFee float64) (*GasPrice, error) {
+
+	history, err := e.Client.FeeHistory(context.Background(), FEE_HISTORY_BLOCKS, nil, []float64{FEE_HISTORY_PERCENTILE})
+	if err != nil {
+		return nil, fmt.Errorf("can not get fee history: %s", err)
+	}
+
+	// the last base fee in history is the base fee of the next block
+	if len(history.BaseFee) == 0 {
+		return nil, fmt.Errorf("received empty fee history")
+	}
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+
+	suggestedTip, err := e.Client.SuggestGasTipCap(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("can not get suggested tip: %s", err)
+	}
+
+	tip := medianReward(history.Reward)
+	if tip == nil || tip.Cmp(suggestedTip) < 0 {
+		tip = suggestedTip
+	}
+
+	return priceEIP1559(baseFee, tip, fromGwei(maxGasFee), fromGwei(maxPriorityFee)), nil
+
+}
+
+// SuggestLegacyPrice gets gas price from the node, bounded by max value in gwei
+func (e *EVMClient) SuggestLegacyPrice(maxGasFee float64) (*GasPrice, error) {
+
+	suggested, err := e.Client.SuggestGasPrice(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("can not get suggested gas price: %s", err)
+	}
+
+	price := &GasPrice{GasFeeCap: suggested}
+
+	maxGasPrice := fromGwei(maxGasFee)
+	if price.GasFeeCap.Cmp(maxGasPrice) > 0 {
+		price.GasFeeCap = maxGasPrice
+		price.Capped = true
+	}
+
+	return price, nil
+
+}
+
+// priceEIP1559 calculates tip and fee cap, limited by max values
+func priceEIP1559(baseFee *big.Int, tip *big.Int, maxFeeCap *big.Int, maxTip *big.Int) *GasPrice {
+
+	price := &GasPrice{BaseFee: baseFee}
+
+	price.GasTipCap = new(big.Int).Set(tip)
+	if price.GasTipCap.Cmp(maxTip) > 0 {
+		price.GasTipCap = new(big.Int).Set(maxTip)
+		price.Capped = true
+	}
+
+	price.GasFeeCap = new(big.Int).Mul(baseFee, big.NewInt(BASE_FEE_MULTIPLIER))
+	price.GasFeeCap.Add(price.GasFeeCap, price.GasTipCap)
+	if price.GasFeeCap.Cmp(maxFeeCap) > 0 {
+		price.GasFeeCap = new(big.Int).Set(maxFeeCap)
+		price.Capped = true
+	}
+
+	// tip can not be higher than fee cap
+	if price.GasTipCap.Cmp(price.GasFeeCap) > 0 {
+		price.GasTipCap = new(big.Int).Set(price.GasFeeCap)
+	}
+
+	return price
+
+}
+
+// medianReward returns median of per block rewards at the requested percentile
+func medianReward(rewards [][]*big.Int) *big.Int {
+
+	var values []*big.Int
+	for _, blockRewards := range rewards {
+		if len(blockRewards) > 0 && blockRewards[0] != nil {
+			values = append(values, blockRewards[0])
+		}
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Cmp(values[j]) < 0
+	})
+
+	return values[len(values)/2]
+
+}
+
+func fromGwei(gwei float64) *big.Int {
+	wei := new(big.Int)
+	wei.SetString(fmt.Sprintf("%.0f", gwei*1e9), 10)
+	return wei
+}
+
+func toGwei(wei *big.Int) string {
+	gwei := new(big.Float).Quo(new(big.Float).SetInt(wei), big.NewFloat(1e9))
+	return gwei.Text('f', 2)
+}
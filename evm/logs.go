@@ -1,16 +1,53 @@
 package evm
 
 import (
-	"context"
+	"bytes"
+	"errors"
+	"fmt"
 	"math/big"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/AccumulateNetwork/bridge/abiutil"
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/labstack/gommon/log"
 )
 
+// ErrStopScan can be returned by ScanBridgeLogs callback to stop scanning without error
+var ErrStopScan = errors.New("stop scan")
+
+// logs query is retried after rate limit errors with exponential backoff
+const LOG_RATE_LIMIT_RETRIES = 5
+const LOG_RATE_LIMIT_BACKOFF = 2 * time.Second
+
+// errors returned by RPC providers when logs query is too large
+var logRangeErrors = []string{
+	"more than 10000 results",
+	"query returned more than",
+	"response size exceeded",
+	"response size should not",
+	"block range",
+	"range too large",
+	"exceed maximum block range",
+	"logs matched by query exceeds",
+	"log response size exceeded",
+}
+
+// errors returned by RPC providers when requests are rate limited, logs query is retried later with the same chunk
+var logRateLimitErrors = []string{
+	"429",
+	"too many requests",
+	"rate limit",
+	"rate exceeded",
+	"request count exceeded",
+	"compute units per second",
+}
+
 type BlockRange struct {
 	From int64
 	To   int64
@@ -19,10 +56,20 @@ type BlockRange struct {
 type EventLog struct {
 	TxID        common.Hash
 	BlockHeight uint64
+	BlockHash   common.Hash
+	LogIndex    uint
 	Token       common.Address
 	Amount      *big.Int
 	To          common.Address
+	From        common.Address // sender of the lock transfer, refunds are sent back to it
 	Destination string
+	Locked      bool // ERC-20 transfer of token in lock mode to the safe, destination is taken from the tx input data
+}
+
+// logSource is a logs query with the parser of its logs
+type logSource struct {
+	query ethereum.FilterQuery
+	parse func(logs []types.Log) ([]*EventLog, error)
 }
 
 // ParseEventLog parses event logs from Ethereum
@@ -30,42 +77,430 @@ func (e *EVMClient) ParseBridgeLogs(eventName string, bridgeAddress string, bloc
 
 	events := []*EventLog{}
 
+	err := e.ScanBridgeLogs(eventName, bridgeAddress, blocks, func(scanned *BlockRange, logs []*EventLog) error {
+		events = append(events, logs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+
+}
+
+// ParseReleaseLogs parses burn logs of the bridge and lock transfers of tokens in lock mode to the safe
+func (e *EVMClient) ParseReleaseLogs(bridgeAddress string, safeAddress string, lockedTokens []string, blocks *BlockRange) ([]*EventLog, error) {
+
+	events := []*EventLog{}
+
+	err := e.ScanReleaseLogs(bridgeAddress, safeAddress, lockedTokens, blocks, func(scanned *BlockRange, logs []*EventLog) error {
+		events = append(events, logs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+
+}
+
+// ScanBridgeLogs parses event logs in chunks of blocks, calling onChunk with sorted logs of every scanned chunk
+// Logs of every chunk are cross-checked with other providers if quorum is configured
+// Chunk is shrinked if RPC provider rejects the query as too large, and grows back after successful queries
+// Rate limited queries are retried with the same chunk after backoff
+func (e *EVMClient) ScanBridgeLogs(eventName string, bridgeAddress string, blocks *BlockRange, onChunk func(scanned *BlockRange, logs []*EventLog) error) error {
+
+	source, err := bridgeLogSource(eventName, bridgeAddress)
+	if err != nil {
+		return err
+	}
+
+	return e.scanLogs([]*logSource{source}, blocks, onChunk)
+
+}
+
+// ScanReleaseLogs scans logs to be released on Accumulate like ScanBridgeLogs: burns of wrapped tokens via bridge and
+// transfers of tokens in lock mode to the safe. Logs of both sources are merged and sorted by block height and log index
+func (e *EVMClient) ScanReleaseLogs(bridgeAddress string, safeAddress string, lockedTokens []string, blocks *BlockRange, onChunk func(scanned *BlockRange, logs []*EventLog) error) error {
+
+	source, err := bridgeLogSource("Burn", bridgeAddress)
+	if err != nil {
+		return err
+	}
+
+	sources := []*logSource{source}
+
+	if len(lockedTokens) > 0 {
+		source, err = e.lockLogSource(safeAddress, lockedTokens)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, source)
+	}
+
+	return e.scanLogs(sources, blocks, onChunk)
+
+}
+
+// bridgeLogSource queries event of the bridge contract
+func bridgeLogSource(eventName string, bridgeAddress string) (*logSource, error) {
+
 	contractAbi, err := abiutil.NewABI([]byte(abiutil.BRIDGE_ABI))
 	if err != nil {
 		return nil, err
 	}
 
+	event, ok := contractAbi.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("event %s not found in bridge abi", eventName)
+	}
+
 	// calculate event hash from event name
-	eventSig := []byte(contractAbi.Events[eventName].Sig)
-	eventHash := crypto.Keccak256Hash(eventSig)
+	eventHash := crypto.Keccak256Hash([]byte(event.Sig))
 
 	// prepate filters for query
-	commonHash := []common.Hash{}
-	commonHash = append(commonHash, eventHash)
-	address := common.HexToAddress(bridgeAddress)
-
 	query := ethereum.FilterQuery{
 		Addresses: []common.Address{
-			address,
+			common.HexToAddress(bridgeAddress),
 		},
 		Topics: [][]common.Hash{
-			commonHash,
+			{eventHash},
 		},
 	}
 
-	if blocks.From > 0 {
-		query.FromBlock = big.NewInt(blocks.From)
+	parse := func(logs []types.Log) ([]*EventLog, error) {
+		return parseEventLogs(contractAbi, eventName, logs), nil
+	}
+
+	return &logSource{query: query, parse: parse}, nil
+
+}
+
+// lockLogSource queries ERC-20 transfers of tokens in lock mode to the safe
+// Accumulate destination is appended to the input data of transfer tx, so txs are fetched for every log
+func (e *EVMClient) lockLogSource(safeAddress string, lockedTokens []string) (*logSource, error) {
+
+	contractAbi, err := abiutil.NewABI([]byte(abiutil.ERC20_ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	eventHash := crypto.Keccak256Hash([]byte(contractAbi.Events["Transfer"].Sig))
+	safe := common.HexToAddress(safeAddress)
+
+	query := ethereum.FilterQuery{
+		Topics: [][]common.Hash{
+			{eventHash},
+			nil,
+			{common.BytesToHash(safe.Bytes())},
+		},
+	}
+
+	for _, token := range lockedTokens {
+		query.Addresses = append(query.Addresses, common.HexToAddress(token))
+	}
+
+	parse := func(logs []types.Log) ([]*EventLog, error) {
+
+		events := []*EventLog{}
+
+		for _, vLog := range logs {
+
+			if len(vLog.Topics) != 3 {
+				continue
+			}
+
+			values, err := contractAbi.Unpack("Transfer", vLog.Data)
+			if err != nil {
+				log.Error(err)
+				continue
+			}
+
+			event := &EventLog{
+				TxID:        vLog.TxHash,
+				BlockHeight: vLog.BlockNumber,
+				BlockHash:   vLog.BlockHash,
+				LogIndex:    vLog.Index,
+				Token:       vLog.Address,
+				Amount:      values[0].(*big.Int),
+				To:          common.BytesToAddress(vLog.Topics[2].Bytes()),
+				From:        common.BytesToAddress(vLog.Topics[1].Bytes()),
+				Locked:      true,
+			}
+
+			event.Destination, err = e.lockDestination(event, safe)
+			if err != nil {
+				return nil, err
+			}
+
+			events = append(events, event)
+
+		}
+
+		return events, nil
+
+	}
+
+	return &logSource{query: query, parse: parse}, nil
+
+}
+
+// lockDestination gets accumulate destination from the input data of the lock tx
+// Tx is cross-checked with other providers if quorum is configured, so that provider can not substitute the destination
+// Destination is empty if the tx is not a direct ERC-20 transfer of the logged amount to the safe, e.g. sent by another contract
+func (e *EVMClient) lockDestination(event *EventLog, safe common.Address) (string, error) {
+
+	tx, receipt, err := getTx(e.Client, event.TxID)
+	if err != nil {
+		return "", err
+	}
+
+	if receipt.BlockHash != event.BlockHash {
+		return "", fmt.Errorf("lock tx %s is in block %s, log is in block %s", event.TxID, receipt.BlockHash, event.BlockHash)
+	}
+
+	err = e.verifyTx(event.TxID, receipt)
+	if err != nil {
+		return "", err
+	}
+
+	if tx.To() == nil || !bytes.Equal(tx.To().Bytes(), event.Token.Bytes()) {
+		return "", nil
+	}
+
+	lock, err := abiutil.UnpackLockTxInputData(tx.Data())
+	if err != nil {
+		log.Debug("lock tx ", event.TxID.Hex(), ": ", err)
+		return "", nil
+	}
+
+	if lock.To != safe || lock.Amount.Cmp(event.Amount) != 0 {
+		return "", nil
+	}
+
+	return lock.Destination, nil
+
+}
+
+// GetLockLog gets ERC-20 transfer to the safe logged by tx at log index, tx is cross-checked with other providers if quorum is configured
+func (e *EVMClient) GetLockLog(safeAddress string, txid common.Hash, logIndex uint) (*EventLog, error) {
+
+	_, receipt, err := getTx(e.Client, txid)
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("tx %s failed", txid)
+	}
+
+	err = e.verifyTx(txid, receipt)
+	if err != nil {
+		return nil, err
+	}
+
+	source, err := e.lockLogSource(safeAddress, nil)
+	if err != nil {
+		return nil, err
 	}
 
-	if blocks.To > 0 {
-		query.ToBlock = big.NewInt(blocks.To)
+	for _, vLog := range receipt.Logs {
+
+		if vLog.Index != logIndex {
+			continue
+		}
+
+		// the log should match the query of lock transfers to the safe
+		if len(vLog.Topics) != 3 || vLog.Topics[0] != source.query.Topics[0][0] || vLog.Topics[2] != source.query.Topics[2][0] {
+			return nil, fmt.Errorf("log %d of tx %s is not a transfer to the safe", logIndex, txid)
+		}
+
+		events, err := source.parse([]types.Log{*vLog})
+		if err != nil {
+			return nil, err
+		}
+		if len(events) != 1 {
+			return nil, fmt.Errorf("can not parse log %d of tx %s", logIndex, txid)
+		}
+
+		return events[0], nil
+
 	}
 
-	logs, err := e.Client.FilterLogs(context.Background(), query)
+	return nil, fmt.Errorf("log %d not found in tx %s", logIndex, txid)
+
+}
+
+// FindRefunds scans ERC-20 transfers of the lock token from the safe back to the lock sender after the lock,
+// returns transfers made by txs with the refund reference in the input data
+func (e *EVMClient) FindRefunds(safeAddress string, lock *EventLog, reference []byte) ([]*EventLog, error) {
+
+	contractAbi, err := abiutil.NewABI([]byte(abiutil.ERC20_ABI))
 	if err != nil {
 		return nil, err
 	}
 
+	eventHash := crypto.Keccak256Hash([]byte(contractAbi.Events["Transfer"].Sig))
+	safe := common.HexToAddress(safeAddress)
+
+	query := ethereum.FilterQuery{
+		Addresses: []common.Address{lock.Token},
+		Topics: [][]common.Hash{
+			{eventHash},
+			{common.BytesToHash(safe.Bytes())},
+			{common.BytesToHash(lock.From.Bytes())},
+		},
+	}
+
+	// refund reference is inside of the safe tx data, which is a part of execTransaction input
+	parse := func(logs []types.Log) ([]*EventLog, error) {
+
+		events := []*EventLog{}
+
+		for _, vLog := range logs {
+
+			tx, _, err := getTx(e.Client, vLog.TxHash)
+			if err != nil {
+				return nil, err
+			}
+
+			if !bytes.Contains(tx.Data(), reference) {
+				continue
+			}
+
+			events = append(events, &EventLog{
+				TxID:        vLog.TxHash,
+				BlockHeight: vLog.BlockNumber,
+				BlockHash:   vLog.BlockHash,
+				LogIndex:    vLog.Index,
+				Token:       vLog.Address,
+				From:        safe,
+				To:          lock.From,
+			})
+
+		}
+
+		return events, nil
+
+	}
+
+	refunds := []*EventLog{}
+
+	err = e.scanLogs([]*logSource{{query: query, parse: parse}}, &BlockRange{From: int64(lock.BlockHeight)}, func(scanned *BlockRange, logs []*EventLog) error {
+		refunds = append(refunds, logs...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+
+}
+
+// scanLogs queries logs of all sources in chunks of blocks, calling onChunk with logs of every chunk, sorted by block height and log index
+func (e *EVMClient) scanLogs(sources []*logSource, blocks *BlockRange, onChunk func(scanned *BlockRange, logs []*EventLog) error) error {
+
+	from := blocks.From
+	to := blocks.To
+
+	// scan up to the latest block
+	if to <= 0 {
+		latest, err := e.latestBlock()
+		if err != nil {
+			return err
+		}
+		to = latest
+	}
+
+	maxChunkSize := e.LogRange
+	if maxChunkSize < 1 {
+		maxChunkSize = 1
+	}
+	chunkSize := maxChunkSize
+	rateLimited := 0
+
+	for from <= to {
+
+		chunk := &BlockRange{From: from, To: from + chunkSize - 1}
+		if chunk.To > to {
+			chunk.To = to
+		}
+
+		events, err := e.queryChunk(sources, chunk)
+		if err != nil {
+			if isRateLimitError(err) && rateLimited < LOG_RATE_LIMIT_RETRIES {
+				backoff := LOG_RATE_LIMIT_BACKOFF << rateLimited
+				rateLimited++
+				log.Debug("logs query for blocks ", chunk.From, "-", chunk.To, " is rate limited, retrying in ", backoff, ": ", err)
+				time.Sleep(backoff)
+				continue
+			}
+			if isLogRangeError(err) && chunkSize > 1 {
+				chunkSize = shrinkChunk(chunkSize)
+				log.Debug("logs query for blocks ", chunk.From, "-", chunk.To, " is too large, retrying with ", chunkSize, " blocks: ", err)
+				continue
+			}
+			return err
+		}
+
+		rateLimited = 0
+
+		err = onChunk(chunk, events)
+		if errors.Is(err, ErrStopScan) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		from = chunk.To + 1
+		chunkSize = growChunk(chunkSize, maxChunkSize)
+
+	}
+
+	return nil
+
+}
+
+// queryChunk queries and parses logs of all sources in the chunk
+func (e *EVMClient) queryChunk(sources []*logSource, chunk *BlockRange) ([]*EventLog, error) {
+
+	events := []*EventLog{}
+
+	for _, source := range sources {
+
+		query := source.query
+		query.FromBlock = big.NewInt(chunk.From)
+		query.ToBlock = big.NewInt(chunk.To)
+
+		logs, err := e.filterLogs(query)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err := source.parse(logs)
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, parsed...)
+
+	}
+
+	sortEventLogs(events)
+
+	return events, nil
+
+}
+
+// parseEventLogs unpacks event logs, sorted by block height and log index
+func parseEventLogs(contractAbi *abi.ABI, eventName string, logs []types.Log) []*EventLog {
+
+	events := []*EventLog{}
+
 	for _, vLog := range logs {
 		event := &EventLog{}
 
@@ -77,10 +512,63 @@ func (e *EVMClient) ParseBridgeLogs(eventName string, bridgeAddress string, bloc
 
 		event.TxID = vLog.TxHash
 		event.BlockHeight = vLog.BlockNumber
+		event.BlockHash = vLog.BlockHash
+		event.LogIndex = vLog.Index
 
 		events = append(events, event)
 	}
 
-	return events, nil
+	sortEventLogs(events)
+
+	return events
+
+}
+
+// sortEventLogs sorts event logs by block height and log index
+func sortEventLogs(events []*EventLog) {
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].BlockHeight != events[j].BlockHeight {
+			return events[i].BlockHeight < events[j].BlockHeight
+		}
+		return events[i].LogIndex < events[j].LogIndex
+	})
+}
+
+// isLogRangeError checks if RPC provider rejected logs query because of block range or number of results
+// Rate limit errors are not range errors, even if they mention exceeded limits
+func isLogRangeError(err error) bool {
+	return !isRateLimitError(err) && containsAny(err, logRangeErrors)
+}
 
+// isRateLimitError checks if RPC provider rejected the query because of rate limits
+func isRateLimitError(err error) bool {
+	return containsAny(err, logRateLimitErrors)
+}
+
+func containsAny(err error, messages []string) bool {
+
+	msg := strings.ToLower(err.Error())
+
+	for _, s := range messages {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func shrinkChunk(size int64) int64 {
+	if size/2 < 1 {
+		return 1
+	}
+	return size / 2
+}
+
+func growChunk(size int64, max int64) int64 {
+	if size*2 > max {
+		return max
+	}
+	return size * 2
 }
@@ -1 +1,71 @@
 package evm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsLogRangeError(t *testing.T) {
+
+	// TEST 1: infura result limit
+	assert.True(t, isLogRangeError(errors.New("query returned more than 10000 results")))
+
+	// TEST 2: block range limit
+	assert.True(t, isLogRangeError(errors.New("eth_getLogs is limited to a 10,000 block range")))
+
+	// TEST 3: other errors are not retried with smaller chunk
+	assert.False(t, isLogRangeError(errors.New("connection refused")))
+
+	// TEST 4: rate limits are temporary and do not shrink the chunk
+	for _, msg := range []string{
+		"429 Too Many Requests: {\"jsonrpc\":\"2.0\",\"error\":{\"code\":429,\"message\":\"Your app has exceeded its compute units per second capacity\"}}",
+		"daily request count exceeded, request rate limited",
+		"project ID request rate exceeded",
+	} {
+		assert.True(t, isRateLimitError(errors.New(msg)), msg)
+		assert.False(t, isLogRangeError(errors.New(msg)), msg)
+	}
+	assert.False(t, isRateLimitError(errors.New("query returned more than 10000 results")))
+
+}
+
+func TestChunkSize(t *testing.T) {
+
+	// TEST 1: chunk is halved down to a single block
+	assert.Equal(t, int64(1000), shrinkChunk(2000))
+	assert.Equal(t, int64(1), shrinkChunk(1))
+
+	// TEST 2: chunk grows back up to max
+	assert.Equal(t, int64(1000), growChunk(500, 2000))
+	assert.Equal(t, int64(2000), growChunk(1500, 2000))
+
+}
+
+func TestCompareLogs(t *testing.T) {
+
+	a := []types.Log{
+		{BlockNumber: 10, BlockHash: common.HexToHash("0x01"), TxHash: common.HexToHash("0xaa"), Index: 1, Data: []byte{1}},
+		{BlockNumber: 10, BlockHash: common.HexToHash("0x01"), TxHash: common.HexToHash("0xbb"), Index: 2, Data: []byte{2}},
+	}
+
+	// TEST 1: same logs in different order
+	assert.NoError(t, compareLogs(a, []types.Log{a[1], a[0]}))
+
+	// TEST 2: missing log
+	assert.Error(t, compareLogs(a, a[:1]))
+
+	// TEST 3: block hash mismatch
+	b := []types.Log{a[0], a[1]}
+	b[1].BlockHash = common.HexToHash("0x02")
+	assert.Error(t, compareLogs(a, b))
+
+	// TEST 4: log data mismatch
+	b = []types.Log{a[0], a[1]}
+	b[1].Data = []byte{3}
+	assert.Error(t, compareLogs(a, b))
+
+}
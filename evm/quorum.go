@@ -0,0 +1,222 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/labstack/gommon/log"
+)
+
+// Provider is additional EVM RPC provider, used to cross-check data of the main node
+type Provider struct {
+	API    string
+	Client *ethclient.Client
+}
+
+// latestBlock returns the highest block known to at least Quorum providers
+func (e *EVMClient) latestBlock() (int64, error) {
+
+	latest, err := e.Client.BlockNumber(context.Background())
+	if err != nil {
+		return 0, err
+	}
+
+	if e.Quorum <= 1 {
+		return int64(latest), nil
+	}
+
+	heights := []uint64{latest}
+	for _, p := range e.Providers {
+		height, err := p.Client.BlockNumber(context.Background())
+		if err != nil {
+			log.Debug("can not get block number from ", p.API, ": ", err)
+			continue
+		}
+		heights = append(heights, height)
+	}
+
+	if len(heights) < e.Quorum {
+		return 0, fmt.Errorf("block number quorum not reached: %d of %d providers responded, %d required", len(heights), len(e.Providers)+1, e.Quorum)
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+
+	return int64(heights[e.Quorum-1]), nil
+
+}
+
+// filterLogs gets logs from the main node and cross-checks them with other providers
+func (e *EVMClient) filterLogs(query ethereum.FilterQuery) ([]types.Log, error) {
+
+	logs, err := e.Client.FilterLogs(context.Background(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.Quorum <= 1 {
+		return logs, nil
+	}
+
+	confirmations := 1
+
+	for _, p := range e.Providers {
+
+		providerLogs, err := p.Client.FilterLogs(context.Background(), query)
+		if err != nil {
+			// chunk should be shrinked for all providers
+			if isLogRangeError(err) {
+				return nil, err
+			}
+			log.Debug("can not get logs from ", p.API, ": ", err)
+			continue
+		}
+
+		err = compareLogs(logs, providerLogs)
+		if err != nil {
+			log.Warn("logs from ", p.API, " do not match logs from the main node: ", err)
+			continue
+		}
+
+		confirmations++
+		if confirmations >= e.Quorum {
+			return logs, nil
+		}
+
+	}
+
+	return nil, fmt.Errorf("logs quorum not reached for blocks %s-%s: %d of %d providers confirmed, %d required", query.FromBlock, query.ToBlock, confirmations, len(e.Providers)+1, e.Quorum)
+
+}
+
+// compareLogs checks if both providers returned the same logs
+func compareLogs(a []types.Log, b []types.Log) error {
+
+	if len(a) != len(b) {
+		return fmt.Errorf("received %d logs, expected %d", len(b), len(a))
+	}
+
+	a = sortLogs(a)
+	b = sortLogs(b)
+
+	for i := range a {
+
+		if a[i].BlockHash != b[i].BlockHash {
+			return fmt.Errorf("block hash mismatch at height %d: %s, expected %s", a[i].BlockNumber, b[i].BlockHash.Hex(), a[i].BlockHash.Hex())
+		}
+
+		if a[i].TxHash != b[i].TxHash || a[i].Index != b[i].Index {
+			return fmt.Errorf("log mismatch at height %d: tx %s log %d, expected tx %s log %d", a[i].BlockNumber, b[i].TxHash.Hex(), b[i].Index, a[i].TxHash.Hex(), a[i].Index)
+		}
+
+		if a[i].Address != b[i].Address || !equalTopics(a[i].Topics, b[i].Topics) || !bytes.Equal(a[i].Data, b[i].Data) || a[i].Removed != b[i].Removed {
+			return fmt.Errorf("log data mismatch in tx %s log %d", a[i].TxHash.Hex(), a[i].Index)
+		}
+
+	}
+
+	return nil
+
+}
+
+// getTx gets tx with receipt and verifies tx hash, so that provider can not substitute tx body
+func getTx(client *ethclient.Client, txid common.Hash) (*types.Transaction, *types.Receipt, error) {
+
+	evmTx, isPending, err := client.TransactionByHash(context.Background(), txid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// check if tx is pending
+	if isPending {
+		return nil, nil, fmt.Errorf("tx %s is pending, skipping", evmTx.Hash())
+	}
+
+	if evmTx.Hash() != txid {
+		return nil, nil, fmt.Errorf("received tx %s, expected %s", evmTx.Hash(), txid)
+	}
+
+	receipt, err := client.TransactionReceipt(context.Background(), txid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if receipt.TxHash != txid {
+		return nil, nil, fmt.Errorf("received receipt of tx %s, expected %s", receipt.TxHash, txid)
+	}
+
+	return evmTx, receipt, nil
+
+}
+
+// verifyTx cross-checks tx inclusion with other providers
+func (e *EVMClient) verifyTx(txid common.Hash, receipt *types.Receipt) error {
+
+	if e.Quorum <= 1 {
+		return nil
+	}
+
+	confirmations := 1
+
+	for _, p := range e.Providers {
+
+		_, providerReceipt, err := getTx(p.Client, txid)
+		if err != nil {
+			log.Debug("can not get tx ", txid, " from ", p.API, ": ", err)
+			continue
+		}
+
+		if providerReceipt.BlockHash != receipt.BlockHash || providerReceipt.Status != receipt.Status {
+			log.Warn("tx ", txid, " from ", p.API, " does not match tx from the main node: block ", providerReceipt.BlockHash, ", expected ", receipt.BlockHash)
+			continue
+		}
+
+		confirmations++
+		if confirmations >= e.Quorum {
+			return nil
+		}
+
+	}
+
+	return fmt.Errorf("tx %s quorum not reached: %d of %d providers confirmed, %d required", txid, confirmations, len(e.Providers)+1, e.Quorum)
+
+}
+
+func sortLogs(logs []types.Log) []types.Log {
+
+	sorted := make([]types.Log, len(logs))
+	copy(sorted, logs)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].BlockNumber != sorted[j].BlockNumber {
+			return sorted[i].BlockNumber < sorted[j].BlockNumber
+		}
+		return sorted[i].Index < sorted[j].Index
+	})
+
+	return sorted
+
+}
+
+func equalTopics(a []common.Hash, b []common.Hash) bool {
+
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+
+}
@@ -0,0 +1,99 @@
+package evm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// PANIC_SELECTOR is selector of solidity Panic(uint256) revert data
+const PANIC_SELECTOR = "0x4e487b71"
+
+// RevertError is returned by Simulate if the call reverts
+type RevertError struct {
+	Reason string // decoded revert reason, empty for custom errors
+	Data   []byte // raw revert data, if returned by the node
+}
+
+func (e *RevertError) Error() string {
+	switch {
+	case e.Reason != "":
+		return "execution reverted: " + e.Reason
+	case len(e.Data) > 0:
+		return "execution reverted with data " + hexutil.Encode(e.Data)
+	}
+	return "execution reverted"
+}
+
+// Simulate calls tx from the client address at the latest block without sending it,
+// returns call output or *RevertError if the tx would revert
+func (e *EVMClient) Simulate(to *common.Address, value int64, data []byte) ([]byte, error) {
+
+	msg := ethereum.CallMsg{
+		From:  e.PublicKey,
+		To:    to,
+		Value: big.NewInt(value),
+		Data:  data,
+	}
+
+	out, err := e.Client.CallContract(context.Background(), msg, nil)
+	if err != nil {
+		if revert := parseRevert(err); revert != nil {
+			return nil, revert
+		}
+		return nil, fmt.Errorf("can not simulate tx: %s", err)
+	}
+
+	return out, nil
+
+}
+
+// parseRevert extracts revert data and reason from eth_call error, returns nil if the error is not a revert
+func parseRevert(err error) *RevertError {
+
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if hexData, ok := dataErr.ErrorData().(string); ok {
+			if data, decodeErr := hexutil.Decode(hexData); decodeErr == nil {
+				return &RevertError{Reason: DecodeRevertReason(data), Data: data}
+			}
+		}
+	}
+
+	// some nodes return the reason in the message only
+	msg := err.Error()
+	i := strings.Index(strings.ToLower(msg), "execution reverted")
+	if i < 0 {
+		return nil
+	}
+
+	reason := strings.TrimSpace(msg[i+len("execution reverted"):])
+	reason = strings.TrimSpace(strings.TrimPrefix(reason, ":"))
+
+	return &RevertError{Reason: reason}
+
+}
+
+// DecodeRevertReason decodes Error(string) and Panic(uint256) revert data, returns empty string for other data
+func DecodeRevertReason(data []byte) string {
+
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		return reason
+	}
+
+	if len(data) == 4+32 && bytes.Equal(data[:4], hexutil.MustDecode(PANIC_SELECTOR)) {
+		return fmt.Sprintf("panic 0x%x", new(big.Int).SetBytes(data[4:]))
+	}
+
+	return ""
+
+}
@@ -0,0 +1,61 @@
+package evm
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// dataError is eth_call error with revert data, as returned by the rpc client
+type dataError struct {
+	msg  string
+	data interface{}
+}
+
+func (e *dataError) Error() string          { return e.msg }
+func (e *dataError) ErrorData() interface{} { return e.data }
+
+func revertData(t *testing.T, reason string) []byte {
+	typ, err := abi.NewType("string", "", nil)
+	assert.NoError(t, err)
+	packed, err := abi.Arguments{{Type: typ}}.Pack(reason)
+	assert.NoError(t, err)
+	return append(hexutil.MustDecode("0x08c379a0"), packed...)
+}
+
+func TestParseRevert(t *testing.T) {
+
+	// TEST 1: Error(string) revert data
+	data := revertData(t, "GS013")
+	revert := parseRevert(&dataError{msg: "execution reverted: GS013", data: hexutil.Encode(data)})
+	assert.NotNil(t, revert)
+	assert.Equal(t, "GS013", revert.Reason)
+	assert.Equal(t, data, revert.Data)
+	assert.Equal(t, "execution reverted: GS013", revert.Error())
+
+	// TEST 2: Panic(uint256) revert data
+	panicData := append(hexutil.MustDecode(PANIC_SELECTOR), common.LeftPadBytes(big.NewInt(0x11).Bytes(), 32)...)
+	revert = parseRevert(&dataError{msg: "execution reverted", data: hexutil.Encode(panicData)})
+	assert.NotNil(t, revert)
+	assert.Equal(t, "panic 0x11", revert.Reason)
+
+	// TEST 3: custom error
+	revert = parseRevert(&dataError{msg: "execution reverted", data: "0x12345678"})
+	assert.NotNil(t, revert)
+	assert.Empty(t, revert.Reason)
+	assert.Equal(t, "execution reverted with data 0x12345678", revert.Error())
+
+	// TEST 4: reason in the message only
+	revert = parseRevert(errors.New("execution reverted: Pausable: paused"))
+	assert.NotNil(t, revert)
+	assert.Equal(t, "Pausable: paused", revert.Reason)
+
+	// TEST 5: not a revert
+	assert.Nil(t, parseRevert(errors.New("connection refused")))
+
+}
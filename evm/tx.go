@@ -2,29 +2,30 @@ package evm
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
 
+// Submit signs and sends tx, gasPrice and priorityFee (in gwei) are the maximum values for the dynamic pricing
 func (e *EVMClient) Submit(gasPrice float64, priorityFee float64, to *common.Address, value int64, data []byte) (*types.Transaction, error) {
-	// Determine which transaction type to create based on chain ID
-	if e.ChainId == 56 {
-		return e.submitLegacyTx(gasPrice, to, value, data)
-	}
-	return e.submitEIP1559Tx(gasPrice, priorityFee, to, value, data)
-}
-
-func (e *EVMClient) submitLegacyTx(gasPrice float64, to *common.Address, value int64, data []byte) (*types.Transaction, error) {
-	chainId := big.NewInt(int64(e.ChainId))
 
-	// Calculate gas price
-	gasFeeCap := new(big.Int)
-	gasFeeCap.SetString(fmt.Sprintf("%.0f", gasPrice*1e9), 10)
+	// Calculate gas fees
+	price, err := e.SuggestPrice(gasPrice, priorityFee)
+	if err != nil {
+		return nil, err
+	}
 
-	txValue := big.NewInt(value)
+	// Estimate gas
+	gasLimit, err := e.EstimateGas(to, value, data)
+	if err != nil {
+		return nil, err
+	}
 
 	// Get nonce
 	fromNonce, err := e.Client.PendingNonceAt(context.Background(), e.PublicKey)
@@ -33,15 +34,50 @@ func (e *EVMClient) submitLegacyTx(gasPrice float64, to *common.Address, value i
 		return nil, err
 	}
 
-	// Create legacy transaction
-	tx := types.NewTx(&types.LegacyTx{
-		Nonce:    fromNonce,
-		GasPrice: gasFeeCap,
-		Gas:      uint64(e.GasLimit),
-		To:       to,
-		Value:    txValue,
-		Data:     data,
-	})
+	return e.SendTx(fromNonce, gasLimit, price, to, value, data)
+
+}
+
+// SuggestPrice suggests tx price depending on chain profile, gasPrice and priorityFee (in gwei) are the maximum values
+func (e *EVMClient) SuggestPrice(gasPrice float64, priorityFee float64) (*GasPrice, error) {
+	if !e.EIP1559 {
+		return e.SuggestLegacyPrice(gasPrice)
+	}
+	return e.SuggestEIP1559Price(gasPrice, priorityFee)
+}
+
+// SendTx signs and sends tx with explicit nonce, gas limit and price
+// Determine which transaction type to create based on chain profile
+func (e *EVMClient) SendTx(nonce uint64, gasLimit uint64, price *GasPrice, to *common.Address, value int64, data []byte) (*types.Transaction, error) {
+
+	chainId := big.NewInt(int64(e.ChainId))
+	txValue := big.NewInt(value)
+
+	var tx *types.Transaction
+
+	if !e.EIP1559 {
+		// Create legacy transaction
+		tx = types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: price.GasFeeCap,
+			Gas:      gasLimit,
+			To:       to,
+			Value:    txValue,
+			Data:     data,
+		})
+	} else {
+		// Create EIP-1559 transaction
+		tx = types.NewTx(&types.DynamicFeeTx{
+			ChainID:   chainId,
+			Nonce:     nonce,
+			GasFeeCap: price.GasFeeCap,
+			GasTipCap: price.GasTipCap,
+			Gas:       gasLimit,
+			To:        to,
+			Value:     txValue,
+			Data:      data,
+		})
+	}
 
 	// Sign and send transaction
 	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainId), e.PrivateKey)
@@ -56,52 +92,48 @@ func (e *EVMClient) submitLegacyTx(gasPrice float64, to *common.Address, value i
 		return nil, err
 	}
 
+	fmt.Println("[evm] tx", signedTx.Hash().Hex(), "nonce", nonce, "gas limit", gasLimit, price)
+
 	return signedTx, nil
-}
 
-func (e *EVMClient) submitEIP1559Tx(gasPrice float64, priorityFee float64, to *common.Address, value int64, data []byte) (*types.Transaction, error) {
-	chainId := big.NewInt(int64(e.ChainId))
+}
 
-	// Calculate gas fees
-	gasFeeCap := new(big.Int)
-	gasFeeCap.SetString(fmt.Sprintf("%.0f", gasPrice*1e9), 10)
+// ErrGasMarginExceeded is returned if estimated gas with the safety margin exceeds gas limit ceiling, the gas limit should be raised
+var ErrGasMarginExceeded = errors.New("gas limit does not fit the safety margin")
 
-	gasTipCap := new(big.Int)
-	gasTipCap.SetString(fmt.Sprintf("%.0f", priorityFee*1e9), 10)
+// EstimateGas estimates tx gas, applies safety multiplier and checks it against gas limit ceiling
+func (e *EVMClient) EstimateGas(to *common.Address, value int64, data []byte) (uint64, error) {
 
-	txValue := big.NewInt(value)
+	msg := ethereum.CallMsg{
+		From:  e.PublicKey,
+		To:    to,
+		Value: big.NewInt(value),
+		Data:  data,
+	}
 
-	// Get nonce
-	fromNonce, err := e.Client.PendingNonceAt(context.Background(), e.PublicKey)
+	estimated, err := e.Client.EstimateGas(context.Background(), msg)
 	if err != nil {
-		fmt.Print("can not get nonce: ")
-		return nil, err
+		return 0, fmt.Errorf("can not estimate gas: %s", err)
 	}
 
-	// Create EIP-1559 transaction
-	tx := types.NewTx(&types.DynamicFeeTx{
-		ChainID:   chainId,
-		Nonce:     fromNonce,
-		GasFeeCap: gasFeeCap,
-		GasTipCap: gasTipCap,
-		Gas:       uint64(e.GasLimit),
-		To:        to,
-		Value:     txValue,
-		Data:      data,
-	})
+	return applyGasMultiplier(estimated, e.GasMultiplier, uint64(e.GasLimit))
 
-	// Sign and send transaction
-	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(chainId), e.PrivateKey)
-	if err != nil {
-		fmt.Print("can not sign tx: ")
-		return nil, err
+}
+
+// applyGasMultiplier multiplies estimated gas and checks it against gas limit ceiling.
+// Estimated gas, which fits the ceiling only without the safety margin, is refused as well, so the tx does not run out of gas
+func applyGasMultiplier(estimated uint64, multiplier float64, ceiling uint64) (uint64, error) {
+
+	gas := uint64(math.Ceil(float64(estimated) * multiplier))
+
+	if estimated > ceiling {
+		return 0, fmt.Errorf("estimated gas %d exceeds gas limit %d", estimated, ceiling)
 	}
 
-	err = e.Client.SendTransaction(context.Background(), signedTx)
-	if err != nil {
-		fmt.Print("can not send tx: ")
-		return nil, err
+	if gas > ceiling {
+		return 0, fmt.Errorf("%w: estimated gas %d with multiplier %g is %d, gas limit is %d", ErrGasMarginExceeded, estimated, multiplier, gas, ceiling)
 	}
 
-	return signedTx, nil
+	return gas, nil
+
 }
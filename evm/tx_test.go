@@ -1 +1,99 @@
 package evm
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyGasMultiplier(t *testing.T) {
+
+	// TEST 1: estimated gas with margin fits the ceiling
+	gas, err := applyGasMultiplier(100000, 1.2, 200000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(120000), gas)
+
+	// TEST 2: margin exceeds the ceiling, the margin is not clipped
+	_, err = applyGasMultiplier(190000, 1.2, 200000)
+	assert.True(t, errors.Is(err, ErrGasMarginExceeded))
+
+	// TEST 3: estimated gas with margin equals the ceiling
+	gas, err = applyGasMultiplier(100000, 2, 200000)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(200000), gas)
+
+	// TEST 4: estimated gas exceeds the ceiling
+	_, err = applyGasMultiplier(210000, 1.2, 200000)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrGasMarginExceeded))
+
+}
+
+func TestPriceEIP1559(t *testing.T) {
+
+	gwei := big.NewInt(1e9)
+	maxFeeCap := new(big.Int).Mul(big.NewInt(30), gwei)
+	maxTip := new(big.Int).Mul(big.NewInt(2), gwei)
+
+	// TEST 1: quiet period, suggested price is below maximums
+	price := priceEIP1559(new(big.Int).Mul(big.NewInt(10), gwei), gwei, maxFeeCap, maxTip)
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(21), gwei), price.GasFeeCap)
+	assert.Equal(t, gwei, price.GasTipCap)
+	assert.False(t, price.Capped)
+
+	// TEST 2: tip and fee cap are limited by maximums
+	price = priceEIP1559(new(big.Int).Mul(big.NewInt(20), gwei), new(big.Int).Mul(big.NewInt(5), gwei), maxFeeCap, maxTip)
+	assert.Equal(t, maxFeeCap, price.GasFeeCap)
+	assert.Equal(t, maxTip, price.GasTipCap)
+	assert.True(t, price.Capped)
+
+	// TEST 3: tip can not exceed fee cap
+	price = priceEIP1559(big.NewInt(0), new(big.Int).Mul(big.NewInt(5), gwei), gwei, maxTip)
+	assert.Equal(t, gwei, price.GasFeeCap)
+	assert.Equal(t, gwei, price.GasTipCap)
+
+}
+
+func TestMedianReward(t *testing.T) {
+
+	assert.Nil(t, medianReward(nil))
+
+	rewards := [][]*big.Int{{big.NewInt(3)}, {big.NewInt(1)}, {}, {big.NewInt(2)}}
+	assert.Equal(t, big.NewInt(2), medianReward(rewards))
+
+}
+
+func TestBumpPrice(t *testing.T) {
+
+	gwei := big.NewInt(1e9)
+	maxFeeCap := new(big.Int).Mul(big.NewInt(30), gwei)
+	maxTip := new(big.Int).Mul(big.NewInt(2), gwei)
+
+	prev := &GasPrice{BaseFee: gwei, GasFeeCap: new(big.Int).Mul(big.NewInt(10), gwei), GasTipCap: gwei}
+
+	// TEST 1: fees did not change, previous price is bumped
+	price, err := bumpPrice(prev, prev, maxFeeCap, maxTip)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(11500000000), price.GasFeeCap)
+	assert.Equal(t, big.NewInt(1150000000), price.GasTipCap)
+
+	// TEST 2: current suggestion is higher than the bump
+	fresh := &GasPrice{BaseFee: gwei, GasFeeCap: new(big.Int).Mul(big.NewInt(20), gwei), GasTipCap: maxTip}
+	price, err = bumpPrice(prev, fresh, maxFeeCap, maxTip)
+	assert.NoError(t, err)
+	assert.Equal(t, fresh.GasFeeCap, price.GasFeeCap)
+	assert.Equal(t, maxTip, price.GasTipCap)
+
+	// TEST 3: previous price is at the maximum, tx can not be replaced
+	_, err = bumpPrice(&GasPrice{GasFeeCap: maxFeeCap, GasTipCap: gwei}, prev, maxFeeCap, maxTip)
+	assert.Error(t, err)
+
+	// TEST 4: legacy price
+	price, err = bumpPrice(&GasPrice{GasFeeCap: gwei}, &GasPrice{GasFeeCap: gwei}, maxFeeCap, maxTip)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1150000000), price.GasFeeCap)
+	assert.Nil(t, price.GasTipCap)
+
+}
@@ -0,0 +1,463 @@
+package evm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	TX_STATUS_PENDING  = "pending"
+	TX_STATUS_SUCCESS  = "success"
+	TX_STATUS_REVERTED = "reverted"
+	TX_STATUS_REPLACED = "replaced" // nonce was used by a tx we did not send
+	TX_FEE_BUMP        = 15         // speed up fee bump in percent, nodes require at least 10
+	TX_HISTORY_LIMIT   = 100        // number of finalized txs to keep
+	TX_MAX_REVERTS     = 3          // reverted tx is not submitted again after this number of reverts
+	TX_REVERT_BACKOFF  = 5 * time.Minute
+)
+
+// ErrRevertBackoff is returned by Submit, if the tx has reverted recently and is retried later
+var ErrRevertBackoff = errors.New("tx reverted recently")
+
+// ErrRevertLimit is returned by Submit, if the tx has reverted TX_MAX_REVERTS times
+var ErrRevertLimit = errors.New("tx reverted too many times")
+
+// ManagedTx is EVM tx tracked by TxManager until it is finalized
+type ManagedTx struct {
+	ID          string // reference set by the caller, e.g. safeTxHash
+	Nonce       uint64 // nonce shared by all tx versions
+	To          *common.Address
+	Value       int64
+	Data        []byte
+	GasLimit    uint64
+	Price       *GasPrice     // price of the latest version
+	Hashes      []common.Hash // hashes of all versions, the latest one last
+	SubmittedAt time.Time     // time the latest version was sent
+	Status      string
+	Receipt     *types.Receipt // receipt of the mined version
+	FinalizedAt time.Time
+	Reverts     int // number of reverted submissions of the tx with this ID
+}
+
+// Hash returns hash of the latest tx version
+func (t *ManagedTx) Hash() common.Hash {
+	return t.Hashes[len(t.Hashes)-1]
+}
+
+// TxManager tracks txs sent from the EVM client key by nonce, waits for receipts
+// and speeds up stuck txs by re-signing them at the same nonce with a higher fee
+type TxManager struct {
+	e            *EVMClient
+	StuckTimeout time.Duration // time since the latest version after which tx is sped up
+	StateFile    string        // tracked txs are saved to the file to survive restarts, not saved if empty
+	mu           sync.Mutex
+	pending      map[uint64]*ManagedTx
+	finalized    map[string]*ManagedTx
+	history      []string // finalized IDs in order, to limit the map size
+}
+
+// txManagerState is the content of the state file
+type txManagerState struct {
+	Pending   []*ManagedTx `json:"pending"`
+	Finalized []*ManagedTx `json:"finalized"` // in history order
+}
+
+// NewTxManager constructs the tx manager, txs tracked before restart are loaded from the state file
+func NewTxManager(e *EVMClient, stuckTimeout time.Duration, stateFile string) (*TxManager, error) {
+
+	if stuckTimeout <= 0 {
+		return nil, fmt.Errorf("stuck tx timeout must be positive, got %s", stuckTimeout)
+	}
+
+	m := &TxManager{
+		e:            e,
+		StuckTimeout: stuckTimeout,
+		StateFile:    stateFile,
+		pending:      make(map[uint64]*ManagedTx),
+		finalized:    make(map[string]*ManagedTx),
+	}
+
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+
+}
+
+// Submit sends tx and starts tracking it under id
+func (m *TxManager) Submit(id string, to *common.Address, value int64, data []byte) (*ManagedTx, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tx := range m.pending {
+		if tx.ID == id {
+			return nil, fmt.Errorf("tx %s is already pending with nonce %d", id, tx.Nonce)
+		}
+	}
+
+	if err := m.checkReverts(id, time.Now()); err != nil {
+		return nil, err
+	}
+
+	price, err := m.e.SuggestPrice(m.e.MaxGasFee, m.e.MaxPriorityFee)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit, err := m.e.EstimateGas(to, value, data)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce, err := m.e.Client.PendingNonceAt(context.Background(), m.e.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("can not get nonce: %s", err)
+	}
+
+	// node may not know about our pending txs yet (e.g. after reconnect)
+	for n := range m.pending {
+		if n >= nonce {
+			nonce = n + 1
+		}
+	}
+
+	sent, err := m.e.SendTx(nonce, gasLimit, price, to, value, data)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &ManagedTx{
+		ID:          id,
+		Nonce:       nonce,
+		To:          to,
+		Value:       value,
+		Data:        data,
+		GasLimit:    gasLimit,
+		Price:       price,
+		Hashes:      []common.Hash{sent.Hash()},
+		SubmittedAt: time.Now(),
+		Status:      TX_STATUS_PENDING,
+	}
+
+	m.pending[nonce] = tx
+	m.save()
+
+	return tx, nil
+
+}
+
+// IsPending returns true if tx with id is sent and not finalized yet
+func (m *TxManager) IsPending(id string) bool {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tx := range m.pending {
+		if tx.ID == id {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// GetFinalized returns finalized tx by id, nil if not found
+func (m *TxManager) GetFinalized(id string) *ManagedTx {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.finalized[id]
+
+}
+
+// Check looks up receipts of pending txs, speeds up stuck ones and returns txs finalized since the last call
+func (m *TxManager) Check() ([]*ManagedTx, error) {
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.pending) == 0 {
+		return nil, nil
+	}
+
+	// nonce of the latest block: all txs below it are mined
+	minedNonce, err := m.e.Client.NonceAt(context.Background(), m.e.PublicKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("can not get nonce: %s", err)
+	}
+
+	var final []*ManagedTx
+
+	// new versions and finalized txs are saved
+	defer m.save()
+
+	for nonce, tx := range m.pending {
+
+		receipt, err := m.findReceipt(tx)
+		if err != nil {
+			return final, err
+		}
+
+		switch {
+		case receipt != nil && receipt.Status == types.ReceiptStatusSuccessful:
+			tx.Status = TX_STATUS_SUCCESS
+		case receipt != nil:
+			tx.Status = TX_STATUS_REVERTED
+		case nonce < minedNonce:
+			// nonce is used, but none of our versions is mined
+			tx.Status = TX_STATUS_REPLACED
+		default:
+			if time.Since(tx.SubmittedAt) >= m.StuckTimeout {
+				if err := m.speedUp(tx); err != nil {
+					fmt.Println("[evm] can not speed up tx", tx.Hash().Hex(), "nonce", tx.Nonce, ":", err)
+				}
+			}
+			continue
+		}
+
+		tx.Receipt = receipt
+		delete(m.pending, nonce)
+		m.finalize(tx)
+		final = append(final, tx)
+
+	}
+
+	return final, nil
+
+}
+
+// findReceipt returns receipt of any tx version, nil if none of them is mined
+func (m *TxManager) findReceipt(tx *ManagedTx) (*types.Receipt, error) {
+
+	for _, hash := range tx.Hashes {
+		receipt, err := m.e.Client.TransactionReceipt(context.Background(), hash)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("can not get receipt of tx %s: %s", hash.Hex(), err)
+		}
+		return receipt, nil
+	}
+
+	return nil, nil
+
+}
+
+// speedUp re-signs tx at the same nonce with a bumped fee
+func (m *TxManager) speedUp(tx *ManagedTx) error {
+
+	fresh, err := m.e.SuggestPrice(m.e.MaxGasFee, m.e.MaxPriorityFee)
+	if err != nil {
+		return err
+	}
+
+	price, err := bumpPrice(tx.Price, fresh, fromGwei(m.e.MaxGasFee), fromGwei(m.e.MaxPriorityFee))
+	if err != nil {
+		return err
+	}
+
+	sent, err := m.e.SendTx(tx.Nonce, tx.GasLimit, price, tx.To, tx.Value, tx.Data)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("[evm] sped up tx", tx.Hash().Hex(), "with", sent.Hash().Hex(), "nonce", tx.Nonce)
+
+	tx.Price = price
+	tx.Hashes = append(tx.Hashes, sent.Hash())
+	tx.SubmittedAt = time.Now()
+
+	return nil
+
+}
+
+func (m *TxManager) finalize(tx *ManagedTx) {
+
+	tx.FinalizedAt = time.Now()
+
+	// reverts are counted across submissions of the same ID
+	if prev, ok := m.finalized[tx.ID]; ok {
+		tx.Reverts = prev.Reverts
+		m.removeHistory(tx.ID)
+	}
+	if tx.Status == TX_STATUS_REVERTED {
+		tx.Reverts++
+	}
+
+	m.finalized[tx.ID] = tx
+	m.history = append(m.history, tx.ID)
+
+	if len(m.history) > TX_HISTORY_LIMIT {
+		delete(m.finalized, m.history[0])
+		m.history = m.history[1:]
+	}
+
+}
+
+func (m *TxManager) removeHistory(id string) {
+	for i, h := range m.history {
+		if h == id {
+			m.history = append(m.history[:i], m.history[i+1:]...)
+			return
+		}
+	}
+}
+
+// checkReverts returns error, if tx with id has reverted and must not be submitted yet or anymore.
+// Backoff doubles with every revert
+func (m *TxManager) checkReverts(id string, now time.Time) error {
+
+	prev, ok := m.finalized[id]
+	if !ok || prev.Status != TX_STATUS_REVERTED {
+		return nil
+	}
+
+	if prev.Reverts >= TX_MAX_REVERTS {
+		return fmt.Errorf("%w: %s reverted %d times", ErrRevertLimit, id, prev.Reverts)
+	}
+
+	retryAt := prev.FinalizedAt.Add(TX_REVERT_BACKOFF << (prev.Reverts - 1))
+	if now.Before(retryAt) {
+		return fmt.Errorf("%w: %s reverted %d times, next retry at %s", ErrRevertBackoff, id, prev.Reverts, retryAt.Format(time.RFC3339))
+	}
+
+	return nil
+
+}
+
+// load restores tracked txs from the state file
+func (m *TxManager) load() error {
+
+	if m.StateFile == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(m.StateFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("can not read tx manager state: %s", err)
+	}
+
+	state := &txManagerState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return fmt.Errorf("can not parse tx manager state %s: %s", m.StateFile, err)
+	}
+
+	for _, tx := range state.Pending {
+		m.pending[tx.Nonce] = tx
+	}
+
+	for _, tx := range state.Finalized {
+		m.finalized[tx.ID] = tx
+		m.history = append(m.history, tx.ID)
+	}
+
+	return nil
+
+}
+
+// save writes tracked txs to the state file, the file is replaced atomically
+func (m *TxManager) save() {
+
+	if m.StateFile == "" {
+		return
+	}
+
+	state := &txManagerState{Pending: []*ManagedTx{}, Finalized: []*ManagedTx{}}
+	for _, tx := range m.pending {
+		state.Pending = append(state.Pending, tx)
+	}
+	for _, id := range m.history {
+		state.Finalized = append(state.Finalized, m.finalized[id])
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		fmt.Println("[evm] can not save tx manager state:", err)
+		return
+	}
+
+	tmp := m.StateFile + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		fmt.Println("[evm] can not save tx manager state:", err)
+		return
+	}
+
+	if err := os.Rename(tmp, m.StateFile); err != nil {
+		fmt.Println("[evm] can not save tx manager state:", err)
+	}
+
+}
+
+// bumpPrice calculates replacement price: at least TX_FEE_BUMP percent above the previous one
+// or the current suggestion if it is higher, limited by max values
+func bumpPrice(prev *GasPrice, fresh *GasPrice, maxFeeCap *big.Int, maxTip *big.Int) (*GasPrice, error) {
+
+	price := &GasPrice{BaseFee: fresh.BaseFee}
+
+	price.GasFeeCap = maxBig(bump(prev.GasFeeCap), fresh.GasFeeCap)
+	if price.GasFeeCap.Cmp(maxFeeCap) > 0 {
+		price.GasFeeCap = new(big.Int).Set(maxFeeCap)
+		price.Capped = true
+	}
+
+	// replacement is rejected by nodes unless all fees are bumped
+	if price.GasFeeCap.Cmp(bump(prev.GasFeeCap)) < 0 {
+		return nil, fmt.Errorf("fee cap %s gwei is limited by config", toGwei(price.GasFeeCap))
+	}
+
+	// legacy tx
+	if prev.GasTipCap == nil {
+		return price, nil
+	}
+
+	price.GasTipCap = maxBig(bump(prev.GasTipCap), fresh.GasTipCap)
+	if price.GasTipCap.Cmp(maxTip) > 0 {
+		price.GasTipCap = new(big.Int).Set(maxTip)
+		price.Capped = true
+	}
+
+	// tip can not be higher than fee cap
+	if price.GasTipCap.Cmp(price.GasFeeCap) > 0 {
+		price.GasTipCap = new(big.Int).Set(price.GasFeeCap)
+	}
+
+	if price.GasTipCap.Cmp(bump(prev.GasTipCap)) < 0 {
+		return nil, fmt.Errorf("tip %s gwei is limited by config", toGwei(price.GasTipCap))
+	}
+
+	return price, nil
+
+}
+
+// bump increases value by TX_FEE_BUMP percent, rounding up
+func bump(value *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(value, big.NewInt(100+TX_FEE_BUMP))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
+
+func maxBig(a *big.Int, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return new(big.Int).Set(a)
+	}
+	return new(big.Int).Set(b)
+}
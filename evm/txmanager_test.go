@@ -0,0 +1,69 @@
+package evm
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxManagerReverts(t *testing.T) {
+
+	var now time.Time
+
+	// TEST 1: zero stuck timeout is rejected
+	_, err := NewTxManager(nil, 0, "")
+	assert.Error(t, err)
+
+	m, err := NewTxManager(nil, time.Minute, "")
+	assert.NoError(t, err)
+
+	// TEST 2: reverted tx is retried with doubling backoff, up to the limit
+	tx := &ManagedTx{ID: "0x01", Hashes: []common.Hash{{1}}, Status: TX_STATUS_REVERTED}
+	m.finalize(tx)
+	assert.Equal(t, 1, m.finalized["0x01"].Reverts)
+	now = tx.FinalizedAt
+	assert.True(t, errors.Is(m.checkReverts("0x01", now), ErrRevertBackoff))
+	assert.NoError(t, m.checkReverts("0x01", now.Add(TX_REVERT_BACKOFF)))
+
+	m.finalize(&ManagedTx{ID: "0x01", Hashes: []common.Hash{{2}}, Status: TX_STATUS_REVERTED})
+	assert.Equal(t, 2, m.finalized["0x01"].Reverts)
+	now = m.finalized["0x01"].FinalizedAt
+	assert.True(t, errors.Is(m.checkReverts("0x01", now.Add(TX_REVERT_BACKOFF)), ErrRevertBackoff))
+	assert.NoError(t, m.checkReverts("0x01", now.Add(2*TX_REVERT_BACKOFF)))
+	assert.Equal(t, []string{"0x01"}, m.history)
+
+	m.finalize(&ManagedTx{ID: "0x01", Hashes: []common.Hash{{3}}, Status: TX_STATUS_REVERTED})
+	assert.True(t, errors.Is(m.checkReverts("0x01", now.Add(time.Hour)), ErrRevertLimit))
+
+	// TEST 3: other txs are not limited
+	assert.NoError(t, m.checkReverts("0x02", now))
+	m.finalize(&ManagedTx{ID: "0x02", Hashes: []common.Hash{{4}}, Status: TX_STATUS_REPLACED})
+	assert.NoError(t, m.checkReverts("0x02", now))
+
+}
+
+func TestTxManagerState(t *testing.T) {
+
+	stateFile := filepath.Join(t.TempDir(), "txs.json")
+
+	m, err := NewTxManager(nil, time.Minute, stateFile)
+	assert.NoError(t, err)
+
+	to := common.HexToAddress("0x24BbA5D6fD7fC2Cbc293FDa6721c9BE6756D177a")
+	m.pending[7] = &ManagedTx{ID: "0x02", Nonce: 7, To: &to, Data: []byte{1}, Hashes: []common.Hash{{2}}, Status: TX_STATUS_PENDING}
+	m.finalize(&ManagedTx{ID: "0x01", Nonce: 6, Hashes: []common.Hash{{1}}, Status: TX_STATUS_REVERTED})
+	m.save()
+
+	// TEST 1: tracked txs are restored after restart
+	restored, err := NewTxManager(nil, time.Minute, stateFile)
+	assert.NoError(t, err)
+	assert.True(t, restored.IsPending("0x02"))
+	assert.Equal(t, to, *restored.pending[7].To)
+	assert.Equal(t, 1, restored.GetFinalized("0x01").Reverts)
+	assert.Equal(t, []string{"0x01"}, restored.history)
+
+}
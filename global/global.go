@@ -1,11 +1,29 @@
 package global
 
-import "github.com/AccumulateNetwork/bridge/schema"
-
-var IsOnline bool                  // is bridge is online or paused
-var IsLeader bool                  // if current node is a leader
-var IsAudit bool                   // if current node is an audit
-var LeaderDuration int64           // number of checks this node is a leader
-var Tokens schema.Tokens           // slice of tokens
-var BridgeFees schema.BridgeFees   // slice of bridge fees
-var LeaderLatestSubmittedTx string // latest submitted EVM tx by the leader
+import (
+	"sync"
+	"time"
+
+	"github.com/AccumulateNetwork/bridge/schema"
+)
+
+var IsOnline bool                      // is bridge is online or paused
+var IsLeader bool                      // if current node is a leader
+var IsAudit bool                       // if current node is an audit
+var LeaderDuration int64               // number of checks this node is a leader
+var LeaderTerm int64                   // term of the current leader lease, carried by queue entries
+var LeaseExpires time.Time             // this node stops acting as leader at expiry, if the lease is not renewed
+var Tokens schema.Tokens               // slice of tokens
+var TokensLock sync.RWMutex            // guards Tokens.Items, which is replaced by the token registry watcher
+var TokenChanges []*schema.TokenChange // latest token registry changes
+var BridgeFees schema.BridgeFees       // slice of bridge fees
+var CreditBalance float64              // credit balance of the bridge key page
+var Censorship schema.CensorshipReport // deposits and burns the leader did not queue within the censorship window
+var CensorshipLock sync.RWMutex        // guards Censorship, which is replaced by the censorship watcher
+var Metrics schema.Metrics             // counters of the node since start
+var MetricsLock sync.RWMutex           // guards Metrics, which is updated by the watchers
+
+// HoldsLease checks if this node is the leader and its lease is not expired
+func HoldsLease() bool {
+	return IsLeader && time.Now().Before(LeaseExpires)
+}
@@ -0,0 +1,357 @@
+package gnosis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+	"github.com/AccumulateNetwork/bridge/binding"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// ErrNotTokenOwner is returned for admin actions on tokens, which are owned neither by the safe nor by the bridge
+var ErrNotTokenOwner = errors.New("token is not owned by the safe or the bridge")
+
+// wrapped token admin actions, executed by the safe
+// Wrapped tokens are owned by the bridge, token calls are executed by the safe during an ownership round trip (see AdminSteps)
+const (
+	ADMIN_PAUSE                    = "pause"                    // token.pause()
+	ADMIN_UNPAUSE                  = "unpause"                  // token.unpause()
+	ADMIN_TRANSFER_OWNERSHIP       = "transfer-ownership"       // token.transferOwnership(newOwner)
+	ADMIN_TRANSFER_TOKEN_OWNERSHIP = "transfer-token-ownership" // bridge.transferTokenOwnership(token, newOwner)
+	ADMIN_RENOUNCE_TOKEN_OWNERSHIP = "renounce-token-ownership" // bridge.renounceTokenOwnership(token)
+)
+
+// AdminAction is administrative action on wrapped token
+type AdminAction struct {
+	Action   string
+	Token    string
+	NewOwner string // only for ownership transfers
+}
+
+func (a *AdminAction) String() string {
+	if a.NewOwner != "" {
+		return fmt.Sprintf("%s token=%s newOwner=%s", a.Action, a.Token, a.NewOwner)
+	}
+	return fmt.Sprintf("%s token=%s", a.Action, a.Token)
+}
+
+// ProposeAdminTx signs safe txs of admin action and proposes them to the safe, returns proposed txs in order of execution.
+// Txs get consecutive nonces after signed txs, which are not executed yet, so that they do not replace pending mints and unlocks
+func (g *Gnosis) ProposeAdminTx(action *AdminAction, safe *SafeState, signed []*MultisigTx) ([]*NewMultisigTx, error) {
+
+	// validate the action before reading the token owner
+	if _, _, err := g.adminTxData(action); err != nil {
+		return nil, err
+	}
+
+	owner, err := g.tokenOwner(action.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	steps, err := g.AdminSteps(action, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := NextAdminNonce(safe.Nonce, signed)
+
+	var txs []*NewMultisigTx
+
+	for i, step := range steps {
+		tx, err := g.signAdminTx(step, nonce+int64(i), "")
+		if err != nil {
+			return txs, err
+		}
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+
+}
+
+// AdminSteps returns admin actions, executed by the safe to apply the action to the token with the given owner.
+// Token calls of bridge owned tokens are wrapped into an ownership round trip: the bridge transfers the token ownership
+// to the safe, the safe calls the token and transfers the ownership back to the bridge, so that mints do not revert afterwards
+func (g *Gnosis) AdminSteps(action *AdminAction, owner common.Address) ([]*AdminAction, error) {
+
+	safe := common.HexToAddress(g.SafeAddress)
+	bridge := common.HexToAddress(g.BridgeAddress)
+
+	isTokenCall := action.Action == ADMIN_PAUSE || action.Action == ADMIN_UNPAUSE || action.Action == ADMIN_TRANSFER_OWNERSHIP
+
+	switch {
+	case owner == safe && isTokenCall:
+		return []*AdminAction{action}, nil
+	case owner == safe:
+		return nil, fmt.Errorf("token %s is owned by the safe, bridge call %s would revert, use %s", action.Token, action.Action, ADMIN_TRANSFER_OWNERSHIP)
+	case owner == bridge && action.Action == ADMIN_TRANSFER_OWNERSHIP:
+		return []*AdminAction{{Action: ADMIN_TRANSFER_TOKEN_OWNERSHIP, Token: action.Token, NewOwner: action.NewOwner}}, nil
+	case owner == bridge && isTokenCall:
+		return []*AdminAction{
+			{Action: ADMIN_TRANSFER_TOKEN_OWNERSHIP, Token: action.Token, NewOwner: safe.Hex()},
+			action,
+			{Action: ADMIN_TRANSFER_OWNERSHIP, Token: action.Token, NewOwner: bridge.Hex()},
+		}, nil
+	case owner == bridge:
+		return []*AdminAction{action}, nil
+	}
+
+	return nil, fmt.Errorf("%w: token %s is owned by %s", ErrNotTokenOwner, action.Token, owner.Hex())
+
+}
+
+// NextAdminNonce returns the nonce after signed txs, which are not executed yet, or the safe nonce if there are none
+func NextAdminNonce(safeNonce int64, signed []*MultisigTx) int64 {
+
+	nonce := safeNonce
+
+	for _, tx := range signed {
+		if !tx.IsExecuted && tx.Nonce >= nonce {
+			nonce = tx.Nonce + 1
+		}
+	}
+
+	return nonce
+
+}
+
+// SignCancelTx signs empty safe tx at nonce, returns the signed tx and safe state to share the signature with.
+// Executed cancellation replaces any pending tx with the same nonce, co-signers run the same command
+func (g *Gnosis) SignCancelTx(nonce int64) (*SafeState, *NewMultisigTx, error) {
+
+	safe, err := g.GetSafeState()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if nonce < safe.Nonce {
+		return nil, nil, fmt.Errorf("nonce %d is already used, current safe nonce is %d", nonce, safe.Nonce)
+	}
+
+	tx, err := g.SignSafeTx(g.NewCancelTx(nonce))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return safe, tx, nil
+
+}
+
+// SignRefundTx signs refund of the lock transfer at nonce, returns the signed tx and safe state to share the signature with.
+// Co-signers run the same command with the same nonce
+func (g *Gnosis) SignRefundTx(tokenAddress string, senderAddress string, amount *big.Int, txid common.Hash, logIndex uint, nonce int64) (*SafeState, *NewMultisigTx, error) {
+
+	safe, err := g.GetSafeState()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if nonce < safe.Nonce {
+		return nil, nil, fmt.Errorf("nonce %d is already used, current safe nonce is %d", nonce, safe.Nonce)
+	}
+
+	refund, err := g.NewRefundTx(tokenAddress, senderAddress, amount, txid, logIndex, nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := g.SignSafeTx(refund)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return safe, tx, nil
+
+}
+
+// ConfirmAdminTx co-signs proposed admin safe tx after verifying that it is the decoded admin action
+func (g *Gnosis) ConfirmAdminTx(tx *MultisigTx) (*AdminAction, error) {
+
+	action, err := g.DecodeAdminTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.signAdminTx(action, tx.Nonce, tx.SafeTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return action, nil
+
+}
+
+// DecodeAdminTx decodes proposed safe tx into admin action, returns error if tx is not an admin action
+func (g *Gnosis) DecodeAdminTx(tx *MultisigTx) (*AdminAction, error) {
+
+	if tx.Value != 0 || tx.Operation != 0 {
+		return nil, fmt.Errorf("admin tx should be a call without value")
+	}
+
+	data, err := hexutil.Decode(tx.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < 4 {
+		return nil, fmt.Errorf("tx data is too short")
+	}
+
+	isBridge := strings.EqualFold(tx.To, g.BridgeAddress)
+
+	contractABI := binding.WrappedTokenMetaData.ABI
+	if isBridge {
+		contractABI = abiutil.BRIDGE_ABI
+	}
+
+	abi, err := abiutil.NewABI([]byte(contractABI))
+	if err != nil {
+		return nil, err
+	}
+
+	method, err := abi.MethodById(data[:4])
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+
+	action := &AdminAction{Token: common.HexToAddress(tx.To).Hex()}
+
+	switch {
+	case !isBridge && method.Name == "pause":
+		action.Action = ADMIN_PAUSE
+	case !isBridge && method.Name == "unpause":
+		action.Action = ADMIN_UNPAUSE
+	case !isBridge && method.Name == "transferOwnership":
+		action.Action = ADMIN_TRANSFER_OWNERSHIP
+		action.NewOwner = args[0].(common.Address).Hex()
+	case isBridge && method.Name == "transferTokenOwnership":
+		action.Action = ADMIN_TRANSFER_TOKEN_OWNERSHIP
+		action.Token = args[0].(common.Address).Hex()
+		action.NewOwner = args[1].(common.Address).Hex()
+	case isBridge && method.Name == "renounceTokenOwnership":
+		action.Action = ADMIN_RENOUNCE_TOKEN_OWNERSHIP
+		action.Token = args[0].(common.Address).Hex()
+	default:
+		return nil, fmt.Errorf("method %s of %s is not an admin action", method.Name, tx.To)
+	}
+
+	// data should be exactly the encoded action
+	_, expected, err := g.adminTxData(action)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(data, expected) {
+		return nil, fmt.Errorf("tx data does not match %s", action)
+	}
+
+	return action, nil
+
+}
+
+// adminTxData returns safe tx target and input data for admin action
+func (g *Gnosis) adminTxData(action *AdminAction) (string, []byte, error) {
+
+	if !common.IsHexAddress(action.Token) {
+		return "", nil, fmt.Errorf("invalid token address %s", action.Token)
+	}
+
+	switch action.Action {
+	case ADMIN_PAUSE:
+		data, err := abiutil.GeneratePauseTxData()
+		return action.Token, data, err
+	case ADMIN_UNPAUSE:
+		data, err := abiutil.GenerateUnpauseTxData()
+		return action.Token, data, err
+	case ADMIN_RENOUNCE_TOKEN_OWNERSHIP:
+		data, err := abiutil.GenerateRenounceTokenOwnershipTxData(action.Token)
+		return g.BridgeAddress, data, err
+	}
+
+	if !common.IsHexAddress(action.NewOwner) {
+		return "", nil, fmt.Errorf("invalid new owner address %s", action.NewOwner)
+	}
+
+	switch action.Action {
+	case ADMIN_TRANSFER_OWNERSHIP:
+		data, err := abiutil.GenerateTransferOwnershipTxData(action.NewOwner)
+		return action.Token, data, err
+	case ADMIN_TRANSFER_TOKEN_OWNERSHIP:
+		data, err := abiutil.GenerateTransferTokenOwnershipTxData(action.Token, action.NewOwner)
+		return g.BridgeAddress, data, err
+	}
+
+	return "", nil, fmt.Errorf("unknown admin action %s", action.Action)
+
+}
+
+// tokenOwner reads the token owner from the chain
+func (g *Gnosis) tokenOwner(token string) (common.Address, error) {
+
+	if g.Client == nil {
+		return common.Address{}, fmt.Errorf("evm node is not configured, can not read token owner from the chain")
+	}
+
+	caller, err := binding.NewWrappedTokenCaller(common.HexToAddress(token), g.Client)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), SAFE_STATE_TIMEOUT)
+	defer cancel()
+
+	owner, err := caller.Owner(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return common.Address{}, fmt.Errorf("can not get owner of token %s: %s", token, err)
+	}
+
+	return owner, nil
+
+}
+
+// checkTokenOwner verifies on chain, that the token is owned by the safe or by the bridge.
+// Either of them may own the token when a step of the ownership round trip is signed, before the previous steps are executed
+func (g *Gnosis) checkTokenOwner(token string) error {
+
+	owner, err := g.tokenOwner(token)
+	if err != nil {
+		return err
+	}
+
+	if owner != common.HexToAddress(g.SafeAddress) && owner != common.HexToAddress(g.BridgeAddress) {
+		return fmt.Errorf("%w: token %s is owned by %s", ErrNotTokenOwner, token, owner.Hex())
+	}
+
+	return nil
+
+}
+
+// signAdminTx signs admin action at nonce and submits the signature to the safe api
+// if safeTxHash is not empty, generated hash should match it
+func (g *Gnosis) signAdminTx(action *AdminAction, nonce int64, safeTxHash string) (*NewMultisigTx, error) {
+
+	to, data, err := g.adminTxData(action)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = g.checkTokenOwner(action.Token); err != nil {
+		return nil, err
+	}
+
+	return g.ProposeSafeTx(g.NewSafeTx(to, data, nonce), safeTxHash)
+
+}
@@ -0,0 +1,166 @@
+package gnosis
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+	"github.com/AccumulateNetwork/bridge/binding"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeToken returns owner for eth_call of owner()
+type fakeToken struct {
+	owner common.Address
+}
+
+func (f *fakeToken) BlockNumber(ctx context.Context) (uint64, error) {
+	return 1, nil
+}
+
+func (f *fakeToken) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (f *fakeToken) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+
+	tokenABI, err := abiutil.NewABI([]byte(binding.WrappedTokenMetaData.ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenABI.Methods["owner"].Outputs.Pack(f.owner)
+
+}
+
+func TestCheckTokenOwner(t *testing.T) {
+
+	g := &Gnosis{}
+	g.SafeAddress = "0x24BbA5D6fD7fC2Cbc293FDa6721c9BE6756D177a"
+	g.BridgeAddress = "0x903f0dA0697FC1c81ecACc83b2A7445F392399e8"
+
+	token := "0x4E780D102AADECF1BdC06d91542cf91960538a2D"
+
+	// TEST 1: safe owns the token
+	g.Client = &fakeToken{owner: common.HexToAddress(g.SafeAddress)}
+	assert.NoError(t, g.checkTokenOwner(token))
+
+	// TEST 2: wrapped token is owned by the bridge
+	g.Client = &fakeToken{owner: common.HexToAddress(g.BridgeAddress)}
+	assert.NoError(t, g.checkTokenOwner(token))
+
+	// TEST 3: token owned by anyone else can not be administered
+	g.Client = &fakeToken{owner: common.HexToAddress("0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314")}
+	assert.True(t, errors.Is(g.checkTokenOwner(token), ErrNotTokenOwner))
+	_, err := g.signAdminTx(&AdminAction{Action: ADMIN_PAUSE, Token: token}, 1, "")
+	assert.True(t, errors.Is(err, ErrNotTokenOwner))
+
+}
+
+func TestAdminSteps(t *testing.T) {
+
+	g := &Gnosis{}
+	g.SafeAddress = "0x24BbA5D6fD7fC2Cbc293FDa6721c9BE6756D177a"
+	g.BridgeAddress = "0x903f0dA0697FC1c81ecACc83b2A7445F392399e8"
+
+	token := "0x4E780D102AADECF1BdC06d91542cf91960538a2D"
+	newOwner := "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"
+
+	safe := common.HexToAddress(g.SafeAddress)
+	bridge := common.HexToAddress(g.BridgeAddress)
+
+	pause := &AdminAction{Action: ADMIN_PAUSE, Token: token}
+
+	// TEST 1: pause of bridge owned token is an ownership round trip
+	steps, err := g.AdminSteps(pause, bridge)
+	assert.NoError(t, err)
+	assert.Equal(t, []*AdminAction{
+		{Action: ADMIN_TRANSFER_TOKEN_OWNERSHIP, Token: token, NewOwner: safe.Hex()},
+		pause,
+		{Action: ADMIN_TRANSFER_OWNERSHIP, Token: token, NewOwner: bridge.Hex()},
+	}, steps)
+
+	// TEST 2: safe owned token is called directly
+	steps, err = g.AdminSteps(pause, safe)
+	assert.NoError(t, err)
+	assert.Equal(t, []*AdminAction{pause}, steps)
+
+	// TEST 3: ownership of bridge owned token is transferred by the bridge
+	steps, err = g.AdminSteps(&AdminAction{Action: ADMIN_TRANSFER_OWNERSHIP, Token: token, NewOwner: newOwner}, bridge)
+	assert.NoError(t, err)
+	assert.Equal(t, []*AdminAction{{Action: ADMIN_TRANSFER_TOKEN_OWNERSHIP, Token: token, NewOwner: newOwner}}, steps)
+
+	// TEST 4: bridge calls of bridge owned token
+	renounce := &AdminAction{Action: ADMIN_RENOUNCE_TOKEN_OWNERSHIP, Token: token}
+	steps, err = g.AdminSteps(renounce, bridge)
+	assert.NoError(t, err)
+	assert.Equal(t, []*AdminAction{renounce}, steps)
+
+	// TEST 5: bridge calls of safe owned token would revert
+	_, err = g.AdminSteps(renounce, safe)
+	assert.Error(t, err)
+
+	// TEST 6: token owned by anyone else
+	_, err = g.AdminSteps(pause, common.HexToAddress(newOwner))
+	assert.True(t, errors.Is(err, ErrNotTokenOwner))
+
+}
+
+func TestAdminNonce(t *testing.T) {
+
+	// TEST 1: no signed txs, admin tx gets the safe nonce
+	assert.Equal(t, int64(5), NextAdminNonce(5, nil))
+
+	// TEST 2: admin tx is proposed after pending mints
+	signed := []*MultisigTx{{Nonce: 5}, {Nonce: 7}, {Nonce: 6}}
+	assert.Equal(t, int64(8), NextAdminNonce(5, signed))
+
+	// TEST 3: executed txs do not hold nonces
+	signed = []*MultisigTx{{Nonce: 5}, {Nonce: 6, IsExecuted: true}}
+	assert.Equal(t, int64(6), NextAdminNonce(5, signed))
+
+}
+
+func TestDecodeAdminTx(t *testing.T) {
+
+	g := &Gnosis{}
+	g.BridgeAddress = "0x903f0dA0697FC1c81ecACc83b2A7445F392399e8"
+
+	token := "0x4E780D102AADECF1BdC06d91542cf91960538a2D"
+	newOwner := "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"
+
+	actions := []*AdminAction{
+		{Action: ADMIN_PAUSE, Token: token},
+		{Action: ADMIN_UNPAUSE, Token: token},
+		{Action: ADMIN_TRANSFER_OWNERSHIP, Token: token, NewOwner: newOwner},
+		{Action: ADMIN_TRANSFER_TOKEN_OWNERSHIP, Token: token, NewOwner: newOwner},
+		{Action: ADMIN_RENOUNCE_TOKEN_OWNERSHIP, Token: token},
+	}
+
+	// TEST 1: encoded actions are decoded back
+	for _, action := range actions {
+		to, data, err := g.adminTxData(action)
+		assert.NoError(t, err)
+
+		decoded, err := g.DecodeAdminTx(&MultisigTx{To: to, Data: hexutil.Encode(data)})
+		assert.NoError(t, err)
+		assert.Equal(t, action, decoded)
+	}
+
+	// TEST 2: mint is not an admin action
+	data, err := abiutil.GenerateMintTxData(token, newOwner, big.NewInt(1e8))
+	assert.NoError(t, err)
+	_, err = g.DecodeAdminTx(&MultisigTx{To: g.BridgeAddress, Data: hexutil.Encode(data)})
+	assert.Error(t, err)
+
+	// TEST 3: admin action with value
+	to, data, _ := g.adminTxData(actions[0])
+	_, err = g.DecodeAdminTx(&MultisigTx{To: to, Data: hexutil.Encode(data), Value: 1})
+	assert.Error(t, err)
+
+}
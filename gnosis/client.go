@@ -2,14 +2,28 @@ package gnosis
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
+const SAFE_API_TIMEOUT = 15 * time.Second
+const SAFE_API_RETRIES = 3
+const SAFE_API_RETRY_DELAY = 1 * time.Second
+const SAFE_API_MAX_RETRY_DELAY = 30 * time.Second
+const SAFE_API_MAX_RESPONSE_SIZE = 10 << 20
+const SAFE_API_PAGE_LIMIT = 100
+const SAFE_API_MAX_PAGES = 100
+
 type ResponseSafe struct {
 	Address         string   `json:"address"`
 	Nonce           string   `json:"nonce"`
@@ -41,8 +55,10 @@ type NewMultisigTx struct {
 }
 
 type ResponseMultisigTxs struct {
-	Count   int64         `json:"count"`
-	Results []*MultisigTx `json:"results"`
+	Count    int64         `json:"count"`
+	Next     *string       `json:"next"`
+	Previous *string       `json:"previous"`
+	Results  []*MultisigTx `json:"results"`
 }
 
 type MultisigTx struct {
@@ -64,12 +80,20 @@ type MultisigTx struct {
 	IsExecuted            bool                      `json:"isExecuted"`
 	ConfirmationsRequired int64                     `json:"confirmationsRequired"`
 	Confirmations         []*MultisigTxConfirmation `json:"confirmations"`
+	Origin                *string                   `json:"origin"`
 }
 
 type MultisigTxs struct {
 	Results []*MultisigTx `json:"results"`
 }
 
+// MultisigTxsFilter filters multisig txs listing, nil fields are not applied
+type MultisigTxsFilter struct {
+	NonceFrom *int64 // nonce >= NonceFrom
+	NonceTo   *int64 // nonce <= NonceTo
+	Executed  *bool
+}
+
 type MultisigTxConfirmation struct {
 	Owner           string     `json:"owner"`
 	SubmissionDate  *time.Time `json:"submissionDate"`
@@ -78,8 +102,47 @@ type MultisigTxConfirmation struct {
 	SignatureType   string     `json:"signatureType"`
 }
 
-type ErrorResponse struct {
-	NonFieldErrors []string `json:"nonFieldErrors"`
+// SAFE_TX_ORIGIN_NAME is the name of the bridge in origin of safe txs it proposes
+const SAFE_TX_ORIGIN_NAME = "accumulate-bridge"
+
+// SafeTxOrigin is origin of safe tx proposed by the bridge, it carries the leader term of the queue entry
+type SafeTxOrigin struct {
+	Name string `json:"name"`
+	Term int64  `json:"term"`
+}
+
+// NewSafeTxOrigin returns origin of the bridge safe tx in safe api format, nil if the term is unknown
+func NewSafeTxOrigin(term int64) *string {
+
+	if term == 0 {
+		return nil
+	}
+
+	originBytes, err := json.Marshal(&SafeTxOrigin{Name: SAFE_TX_ORIGIN_NAME, Term: term})
+	if err != nil {
+		return nil
+	}
+
+	origin := string(originBytes)
+
+	return &origin
+
+}
+
+// Term returns leader term from origin of the safe tx, 0 if the tx is not proposed by the bridge or has no term
+func (tx *MultisigTx) Term() int64 {
+
+	if tx.Origin == nil {
+		return 0
+	}
+
+	origin := &SafeTxOrigin{}
+	if err := json.Unmarshal([]byte(*tx.Origin), origin); err != nil || origin.Name != SAFE_TX_ORIGIN_NAME {
+		return 0
+	}
+
+	return origin.Term
+
 }
 
 // GetSafe gets safe info and current nonce
@@ -108,41 +171,18 @@ func (g *Gnosis) CreateSafeMultisigTx(data *NewMultisigTx) error {
 		return err
 	}
 
-	body, err := g.makeRequest("safes/"+g.SafeAddress+"/multisig-transactions/", params)
-	if err != nil {
-		return err
-	}
+	// gnosis safe api returns 201 with empty response if everything is OK,
+	// errors are returned by makeRequest as *APIError
+	_, err = g.makeRequest("safes/"+g.SafeAddress+"/multisig-transactions/", params)
 
-	var resp ErrorResponse
-
-	// gnosis safe api returns empty response if everything is OK
-	// unmarshal message only if body is not empty
-	if len(body) > 0 {
-		if err = json.Unmarshal(body, &resp); err != nil {
-			return err
-		}
-		return fmt.Errorf(resp.NonFieldErrors[0])
-	}
-
-	return nil
+	return err
 
 }
 
-// GetSafeMultisigTx gets multisig tx from gnosis safe API
+// GetSafeMultisigTxByNonce gets all multisig txs with the nonce from gnosis safe API
 func (g *Gnosis) GetSafeMultisigTxByNonce(nonce int64) (*MultisigTxs, error) {
 
-	body, err := g.makeRequest("safes/"+g.SafeAddress+"/multisig-transactions/?nonce="+strconv.FormatInt(nonce, 10), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	var resp MultisigTxs
-
-	if err = json.Unmarshal(body, &resp); err != nil {
-		return nil, err
-	}
-
-	return &resp, nil
+	return g.QuerySafeMultisigTxs(&MultisigTxsFilter{NonceFrom: &nonce, NonceTo: &nonce})
 
 }
 
@@ -164,44 +204,211 @@ func (g *Gnosis) GetSafeMultisigTx(safeTxHash string) (*MultisigTx, error) {
 
 }
 
-// GetSafeMultisigTxs gets multisig txs from gnosis safe API
+// GetSafeMultisigTxs gets all multisig txs from gnosis safe API
 func (g *Gnosis) GetSafeMultisigTxs() (*MultisigTxs, error) {
 
-	body, err := g.makeRequest("safes/"+g.SafeAddress+"/multisig-transactions/", nil)
-	if err != nil {
-		return nil, err
+	return g.QuerySafeMultisigTxs(&MultisigTxsFilter{})
+
+}
+
+// QuerySafeMultisigTxs gets all pages of multisig txs matching the filter from gnosis safe API,
+// txs are ordered by nonce, txs with the same nonce by submission date
+func (g *Gnosis) QuerySafeMultisigTxs(filter *MultisigTxsFilter) (*MultisigTxs, error) {
+
+	params := url.Values{}
+	// several txs may have the same nonce, creation date is the tiebreaker, so that offset pages do not overlap
+	params.Set("ordering", "nonce,created")
+	params.Set("limit", strconv.Itoa(SAFE_API_PAGE_LIMIT))
+
+	if filter.NonceFrom != nil {
+		params.Set("nonce__gte", strconv.FormatInt(*filter.NonceFrom, 10))
+	}
+	if filter.NonceTo != nil {
+		params.Set("nonce__lte", strconv.FormatInt(*filter.NonceTo, 10))
+	}
+	if filter.Executed != nil {
+		params.Set("executed", strconv.FormatBool(*filter.Executed))
 	}
 
-	var resp MultisigTxs
+	resp := &MultisigTxs{}
+
+	// txs proposed while paging shift the pages, so txs already read are skipped by safeTxHash
+	seen := make(map[string]bool)
+	offset := 0
+
+	for page := 0; ; page++ {
+
+		if page >= SAFE_API_MAX_PAGES {
+			return nil, fmt.Errorf("gnosis safe api returned more than %d pages of multisig txs", SAFE_API_MAX_PAGES)
+		}
+
+		// offset is used instead of next URL, which may point to the service's internal host
+		params.Set("offset", strconv.Itoa(offset))
+
+		body, err := g.makeRequest("safes/"+g.SafeAddress+"/multisig-transactions/?"+params.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var pageResp ResponseMultisigTxs
+
+		if err = json.Unmarshal(body, &pageResp); err != nil {
+			return nil, err
+		}
+
+		offset += len(pageResp.Results)
+
+		for _, tx := range pageResp.Results {
+			hash := strings.ToLower(tx.SafeTxHash)
+			if seen[hash] {
+				continue
+			}
+			seen[hash] = true
+			resp.Results = append(resp.Results, tx)
+		}
+
+		if pageResp.Next == nil || len(pageResp.Results) == 0 || int64(offset) >= pageResp.Count {
+			break
+		}
 
-	if err = json.Unmarshal(body, &resp); err != nil {
-		return nil, err
 	}
 
-	return &resp, nil
+	// do not rely on the service ordering
+	sortMultisigTxs(resp.Results)
+
+	return resp, nil
 
 }
 
-// internal function that sends API requests
+// sortMultisigTxs sorts txs by nonce, txs with the same nonce by submission date
+func sortMultisigTxs(txs []*MultisigTx) {
+
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].Nonce != txs[j].Nonce {
+			return txs[i].Nonce < txs[j].Nonce
+		}
+		if txs[i].SubmissionDate == nil || txs[j].SubmissionDate == nil {
+			return txs[i].SubmissionDate != nil
+		}
+		return txs[i].SubmissionDate.Before(*txs[j].SubmissionDate)
+	})
+
+}
+
+// internal function that sends API requests, retries on network errors, 429 and 5xx
 func (g *Gnosis) makeRequest(path string, req []byte) ([]byte, error) {
 
-	var resp *http.Response
-	var err error
+	ctx, cancel := context.WithTimeout(context.Background(), (SAFE_API_RETRIES+1)*(SAFE_API_TIMEOUT+SAFE_API_MAX_RETRY_DELAY))
+	defer cancel()
+
+	return g.makeRequestContext(ctx, path, req)
+
+}
+
+func (g *Gnosis) makeRequestContext(ctx context.Context, path string, req []byte) ([]byte, error) {
+
+	delay := SAFE_API_RETRY_DELAY
+
+	for attempt := 0; ; attempt++ {
+
+		body, retryAfter, err := g.doRequest(ctx, path, req)
+		if err == nil {
+			return body, nil
+		}
+
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !apiErr.Temporary() {
+			return nil, err
+		}
+
+		if attempt >= SAFE_API_RETRIES || ctx.Err() != nil {
+			return nil, err
+		}
+
+		wait := delay
+		if retryAfter > 0 {
+			wait = retryAfter
+		}
+		if wait > SAFE_API_MAX_RETRY_DELAY {
+			wait = SAFE_API_MAX_RETRY_DELAY
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return nil, err
+		}
 
+		delay *= 2
+
+	}
+
+}
+
+// doRequest makes single API request, returns response body if status is 2xx, *APIError otherwise
+func (g *Gnosis) doRequest(ctx context.Context, path string, req []byte) ([]byte, time.Duration, error) {
+
+	ctx, cancel := context.WithTimeout(ctx, SAFE_API_TIMEOUT)
+	defer cancel()
+
+	method := http.MethodGet
+	var reqBody io.Reader
 	if req != nil {
-		resp, err = http.Post(g.API+path, "application/json", bytes.NewBuffer(req))
-	} else {
-		resp, err = http.Get(g.API + path)
+		method = http.MethodPost
+		reqBody = bytes.NewReader(req)
 	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, g.API+path, reqBody)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if req != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if g.APIKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+g.APIKey)
 	}
 
-	body, err := ioutil.ReadAll(resp.Body)
+	resp, err := http.DefaultClient.Do(httpReq)
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, SAFE_API_MAX_RESPONSE_SIZE))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), parseAPIError(resp.StatusCode, body)
+	}
+
+	return body, 0, nil
+
+}
+
+// parseRetryAfter parses Retry-After header, which is either delay in seconds or http date
+func parseRetryAfter(value string) time.Duration {
+
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
 	}
 
-	return body, nil
+	return 0
 
 }
@@ -0,0 +1,292 @@
+package gnosis
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// gnosis safe signature types by v
+const (
+	SIG_V_CONTRACT      = 0  // EIP-1271 contract signature: r = owner contract, s = offset of signature data
+	SIG_V_APPROVED_HASH = 1  // approved hash: r = owner, that approved the hash on chain or executes the tx
+	SIG_V_EIP712        = 27 // EIP-712 signature of safe tx hash: v = 27 or 28
+	SIG_V_ETH_SIGN      = 31 // eth_sign signature of safe tx hash: v = 31 or 32
+)
+
+// OwnerSignature is verified signature of a safe owner
+type OwnerSignature struct {
+	Owner   common.Address
+	Static  []byte // r, s, v
+	Dynamic []byte // signature data of contract owner
+}
+
+// VerifyConfirmations verifies confirmations of safe tx and recovers their signers. Invalid, duplicate
+// and non-owner signatures are dropped. Returns valid signatures ordered by owner address
+func (g *Gnosis) VerifyConfirmations(safe *SafeState, tx *MultisigTx) ([]*OwnerSignature, error) {
+
+	hash, preimage, err := g.verifyMultisigTxHash(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var sigs []*OwnerSignature
+	seen := make(map[common.Address]bool)
+
+	for _, con := range tx.Confirmations {
+
+		sig, err := g.verifySignature(safe, hash, preimage, con.Signature)
+		if err != nil {
+			fmt.Println("[gnosis] dropping signature of", con.Owner, "for safetxhash", tx.SafeTxHash, err)
+			continue
+		}
+
+		if con.Owner != "" && !strings.EqualFold(con.Owner, sig.Owner.Hex()) {
+			fmt.Println("[gnosis] dropping signature of", con.Owner, "for safetxhash", tx.SafeTxHash, "signed by", sig.Owner.Hex())
+			continue
+		}
+
+		if seen[sig.Owner] {
+			continue
+		}
+		seen[sig.Owner] = true
+
+		sigs = append(sigs, sig)
+
+	}
+
+	sortOwnerSignatures(sigs)
+
+	return sigs, nil
+
+}
+
+// EncodeSignatures encodes signatures for execTransaction: static parts ordered by owner address,
+// followed by signature data of contract owners
+func EncodeSignatures(sigs []*OwnerSignature) []byte {
+
+	sorted := make([]*OwnerSignature, len(sigs))
+	copy(sorted, sigs)
+	sortOwnerSignatures(sorted)
+
+	var static, dynamic []byte
+	staticLength := crypto.SignatureLength * len(sorted)
+
+	for _, sig := range sorted {
+
+		s := make([]byte, crypto.SignatureLength)
+		copy(s, sig.Static)
+
+		if s[64] == SIG_V_CONTRACT {
+			offset := big.NewInt(int64(staticLength + len(dynamic)))
+			copy(s[32:64], common.LeftPadBytes(offset.Bytes(), 32))
+			dynamic = append(dynamic, common.LeftPadBytes(big.NewInt(int64(len(sig.Dynamic))).Bytes(), 32)...)
+			dynamic = append(dynamic, sig.Dynamic...)
+		}
+
+		static = append(static, s...)
+
+	}
+
+	return append(static, dynamic...)
+
+}
+
+func sortOwnerSignatures(sigs []*OwnerSignature) {
+	sort.Slice(sigs, func(i, j int) bool {
+		return bytes.Compare(sigs[i].Owner.Bytes(), sigs[j].Owner.Bytes()) < 0
+	})
+}
+
+// verifyMultisigTxHash checks that safeTxHash of tx matches its params, returns the hash and its preimage
+func (g *Gnosis) verifyMultisigTxHash(tx *MultisigTx) ([]byte, []byte, error) {
+
+	safeTx, err := g.SafeTxFromMultisigTx(tx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	preimage, err := safeTx.HashData()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hash := crypto.Keccak256(preimage)
+
+	if hexutil.Encode(hash) != strings.ToLower(tx.SafeTxHash) {
+		return nil, nil, fmt.Errorf("safe tx hash %s does not match tx params, expected %s", tx.SafeTxHash, hexutil.Encode(hash))
+	}
+
+	return hash, preimage, nil
+
+}
+
+// verifySignature verifies signature of any gnosis safe signature type and checks that the signer is a safe owner
+func (g *Gnosis) verifySignature(safe *SafeState, hash []byte, preimage []byte, signature string) (*OwnerSignature, error) {
+
+	sigBytes, err := hexutil.Decode(signature)
+	if err != nil {
+		return nil, fmt.Errorf("can not decode signature: %s", err)
+	}
+
+	if len(sigBytes) < crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length %d", len(sigBytes))
+	}
+
+	sig := &OwnerSignature{}
+	sig.Static = make([]byte, crypto.SignatureLength)
+	copy(sig.Static, sigBytes)
+
+	v := sig.Static[64]
+
+	switch {
+	case v == SIG_V_CONTRACT:
+		sig.Owner = common.BytesToAddress(sig.Static[:32])
+		sig.Dynamic, err = contractSignatureData(sigBytes)
+		if err != nil {
+			return nil, err
+		}
+		if !safe.IsOwner(sig.Owner) {
+			break
+		}
+		valid, err := g.isValidContractSignature(sig.Owner, preimage, sig.Dynamic)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			return nil, fmt.Errorf("contract signature is rejected by %s", sig.Owner.Hex())
+		}
+	case v == SIG_V_APPROVED_HASH:
+		sig.Owner = common.BytesToAddress(sig.Static[:32])
+		// executor's own approval does not need an approveHash tx
+		if sig.Owner == g.PublicKey || !safe.IsOwner(sig.Owner) {
+			break
+		}
+		approved, err := g.isApprovedHash(sig.Owner, hash)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			return nil, fmt.Errorf("hash is not approved by %s", sig.Owner.Hex())
+		}
+	case v == SIG_V_EIP712 || v == SIG_V_EIP712+1:
+		sig.Owner, err = recoverAddress(hash, sig.Static, v-SIG_V_EIP712)
+		if err != nil {
+			return nil, err
+		}
+	case v == SIG_V_ETH_SIGN || v == SIG_V_ETH_SIGN+1:
+		sig.Owner, err = recoverAddress(accounts.TextHash(hash), sig.Static, v-SIG_V_ETH_SIGN)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported signature v=%d", v)
+	}
+
+	if !safe.IsOwner(sig.Owner) {
+		return nil, fmt.Errorf("signer %s is not a safe owner", sig.Owner.Hex())
+	}
+
+	return sig, nil
+
+}
+
+// contractSignatureData extracts signature data of contract owner, located at offset s after 32 bytes length
+func contractSignatureData(signature []byte) ([]byte, error) {
+
+	offset := new(big.Int).SetBytes(signature[32:64])
+	if !offset.IsUint64() || offset.Uint64() < crypto.SignatureLength || offset.Uint64()+32 > uint64(len(signature)) {
+		return nil, fmt.Errorf("invalid contract signature offset %s", offset)
+	}
+
+	start := offset.Uint64() + 32
+
+	length := new(big.Int).SetBytes(signature[offset.Uint64():start])
+	if !length.IsUint64() || start+length.Uint64() > uint64(len(signature)) {
+		return nil, fmt.Errorf("invalid contract signature length %s", length)
+	}
+
+	return signature[start : start+length.Uint64()], nil
+
+}
+
+func recoverAddress(hash []byte, signature []byte, recoveryId byte) (common.Address, error) {
+
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+	sig[64] = recoveryId
+
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+
+}
+
+// isApprovedHash checks if owner approved the hash with approveHash tx
+func (g *Gnosis) isApprovedHash(owner common.Address, hash []byte) (bool, error) {
+
+	var out []interface{}
+	if err := g.callContract(g.SafeAddress, abiutil.GNOSIS_ABI, &out, "approvedHashes", owner, common.BytesToHash(hash)); err != nil {
+		return false, fmt.Errorf("can not check approved hash: %s", err)
+	}
+
+	approved, ok := out[0].(*big.Int)
+	if !ok {
+		return false, fmt.Errorf("unexpected approvedHashes result")
+	}
+
+	return approved.Sign() != 0, nil
+
+}
+
+// isValidContractSignature checks signature of contract owner with EIP-1271 isValidSignature
+func (g *Gnosis) isValidContractSignature(owner common.Address, preimage []byte, signature []byte) (bool, error) {
+
+	var out []interface{}
+	if err := g.callContract(owner.Hex(), abiutil.EIP1271_ABI, &out, "isValidSignature", preimage, signature); err != nil {
+		// reverts are treated as invalid signatures by gnosis safe as well
+		fmt.Println("[gnosis] isValidSignature call failed:", err)
+		return false, nil
+	}
+
+	magic, ok := out[0].([4]byte)
+	if !ok {
+		return false, fmt.Errorf("unexpected isValidSignature result")
+	}
+
+	return hexutil.Encode(magic[:]) == abiutil.EIP1271_MAGIC_VALUE, nil
+
+}
+
+func (g *Gnosis) callContract(address string, contractABI string, out *[]interface{}, method string, params ...interface{}) error {
+
+	if g.Client == nil {
+		return fmt.Errorf("evm node is not configured")
+	}
+
+	parsed, err := abiutil.NewABI([]byte(contractABI))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), SAFE_STATE_TIMEOUT)
+	defer cancel()
+
+	contract := bind.NewBoundContract(common.HexToAddress(address), *parsed, g.Client, nil, nil)
+
+	return contract.Call(&bind.CallOpts{Context: ctx}, out, method, params...)
+
+}
@@ -0,0 +1,153 @@
+package gnosis
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// contractSignature encodes signature of contract owner with signature data at offset 65
+func contractSignature(owner common.Address, data []byte) string {
+	sig := common.LeftPadBytes(owner.Bytes(), 32)
+	sig = append(sig, common.LeftPadBytes(big.NewInt(65).Bytes(), 32)...)
+	sig = append(sig, SIG_V_CONTRACT)
+	sig = append(sig, common.LeftPadBytes(big.NewInt(int64(len(data))).Bytes(), 32)...)
+	return hexutil.Encode(append(sig, data...))
+}
+
+// approvedHashSignature encodes approved hash signature of the owner
+func approvedHashSignature(owner common.Address) string {
+	sig := common.LeftPadBytes(owner.Bytes(), 32)
+	sig = append(sig, make([]byte, 32)...)
+	return hexutil.Encode(append(sig, SIG_V_APPROVED_HASH))
+}
+
+func signHash(t *testing.T, hash []byte, key *ecdsa.PrivateKey, v byte) string {
+	sig, err := crypto.Sign(hash, key)
+	assert.NoError(t, err)
+	sig[64] += v
+	return hexutil.Encode(sig)
+}
+
+func TestVerifyConfirmations(t *testing.T) {
+
+	g := &Gnosis{ChainId: 5, SafeAddress: "0x24BbA5D6fD7fC2Cbc293FDa6721c9BE6756D177a", BridgeAddress: "0x903f0dA0697FC1c81ecACc83b2A7445F392399e8"}
+	_, err := g.ImportPrivateKey("08108aadbbe82e9ffa1eba54158e7aacbec6115156242558ae7e594037220e4a")
+	assert.NoError(t, err)
+
+	key1, _ := crypto.GenerateKey()
+	key2, _ := crypto.GenerateKey()
+	notOwnerKey, _ := crypto.GenerateKey()
+	owner1 := crypto.PubkeyToAddress(key1.PublicKey)
+	owner2 := crypto.PubkeyToAddress(key2.PublicKey)
+	approver := common.HexToAddress("0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314")
+	notApprover := common.HexToAddress("0x4E780D102AADECF1BdC06d91542cf91960538a2D")
+	contractOwner := common.HexToAddress("0x903f0dA0697FC1c81ecACc83b2A7445F392399e8")
+
+	safe := &SafeState{Nonce: 7, Threshold: 3, Owners: []common.Address{g.PublicKey, owner1, owner2, approver, notApprover, contractOwner}}
+
+	g.Client = &fakeSafe{
+		approved:     map[common.Address]bool{approver: true},
+		contractSigs: map[common.Address][]byte{contractOwner: []byte("valid")},
+	}
+
+	data, err := abiutil.GenerateMintTxData("0x4E780D102AADECF1BdC06d91542cf91960538a2D", "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314", big.NewInt(1e8))
+	assert.NoError(t, err)
+
+	hash, err := g.NewSafeTx(g.BridgeAddress, data, 7).Hash()
+	assert.NoError(t, err)
+
+	tx := &MultisigTx{To: g.BridgeAddress, Data: hexutil.Encode(data), Nonce: 7, SafeTxHash: hexutil.Encode(hash)}
+
+	// TEST 1: every signature type
+	tx.Confirmations = []*MultisigTxConfirmation{
+		{Owner: owner1.Hex(), Signature: signHash(t, hash, key1, SIG_V_EIP712)},
+		{Owner: owner2.Hex(), Signature: signHash(t, accounts.TextHash(hash), key2, SIG_V_ETH_SIGN)},
+		{Owner: approver.Hex(), Signature: approvedHashSignature(approver)},
+		{Owner: g.PublicKey.Hex(), Signature: approvedHashSignature(g.PublicKey)},
+		{Owner: contractOwner.Hex(), Signature: contractSignature(contractOwner, []byte("valid"))},
+	}
+
+	sigs, err := g.VerifyConfirmations(safe, tx)
+	assert.NoError(t, err)
+	assert.Len(t, sigs, 5)
+	for i := 1; i < len(sigs); i++ {
+		assert.Equal(t, -1, bytes.Compare(sigs[i-1].Owner.Bytes(), sigs[i].Owner.Bytes()))
+	}
+
+	// TEST 2: invalid signatures are dropped
+	wrongHash := crypto.Keccak256([]byte("wrong"))
+	tx.Confirmations = []*MultisigTxConfirmation{
+		{Owner: owner1.Hex(), Signature: signHash(t, hash, key1, SIG_V_EIP712)},
+		{Owner: owner1.Hex(), Signature: signHash(t, hash, key1, SIG_V_EIP712)},                      // duplicate
+		{Owner: owner2.Hex(), Signature: signHash(t, wrongHash, key2, SIG_V_EIP712)},                 // wrong hash
+		{Owner: owner2.Hex(), Signature: signHash(t, hash, key2, SIG_V_ETH_SIGN)},                    // eth_sign of raw hash
+		{Owner: "", Signature: signHash(t, hash, notOwnerKey, SIG_V_EIP712)},                         // not an owner
+		{Owner: owner2.Hex(), Signature: signHash(t, hash, key1, SIG_V_EIP712)},                      // owner mismatch
+		{Owner: notApprover.Hex(), Signature: approvedHashSignature(notApprover)},                    // not approved
+		{Owner: contractOwner.Hex(), Signature: contractSignature(contractOwner, []byte("invalid"))}, // rejected by contract
+		{Owner: contractOwner.Hex(), Signature: hexutil.Encode(common.LeftPadBytes([]byte{0}, 65))},  // invalid offset
+		{Owner: owner2.Hex(), Signature: "0x1234"},                                                   // malformed
+		{Owner: owner2.Hex(), Signature: hexutil.Encode(append(make([]byte, 64), 29))},               // unsupported v
+	}
+
+	sigs, err = g.VerifyConfirmations(safe, tx)
+	assert.NoError(t, err)
+	assert.Len(t, sigs, 1)
+	assert.Equal(t, owner1, sigs[0].Owner)
+
+	// TEST 3: safe tx hash does not match tx params
+	tx.Nonce = 8
+	_, err = g.VerifyConfirmations(safe, tx)
+	assert.Error(t, err)
+	tx.Nonce = 7
+
+	// TEST 4: value is a part of the hash
+	tx.Value = 1
+	_, err = g.VerifyConfirmations(safe, tx)
+	assert.Error(t, err)
+
+}
+
+func TestEncodeSignatures(t *testing.T) {
+
+	owner1 := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	owner2 := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	owner3 := common.HexToAddress("0x0000000000000000000000000000000000000003")
+
+	eoa := append(bytes.Repeat([]byte{1}, 64), 27)
+	approved := append(common.LeftPadBytes(owner1.Bytes(), 32), make([]byte, 32)...)
+	approved = append(approved, SIG_V_APPROVED_HASH)
+	contract := append(common.LeftPadBytes(owner2.Bytes(), 32), make([]byte, 32)...)
+	contract = append(contract, SIG_V_CONTRACT)
+
+	sigs := []*OwnerSignature{
+		{Owner: owner3, Static: eoa},
+		{Owner: owner2, Static: contract, Dynamic: []byte("data")},
+		{Owner: owner1, Static: approved},
+	}
+
+	encoded := EncodeSignatures(sigs)
+
+	// TEST 1: static parts are ordered by owner, contract signature data is appended
+	assert.Len(t, encoded, 65*3+32+4)
+	assert.Equal(t, approved, encoded[:65])
+	assert.Equal(t, owner2.Bytes(), encoded[65+12:65+32])
+	assert.Equal(t, common.LeftPadBytes(big.NewInt(65*3).Bytes(), 32), encoded[65+32:65+64])
+	assert.Equal(t, eoa, encoded[130:195])
+	assert.Equal(t, common.LeftPadBytes(big.NewInt(4).Bytes(), 32), encoded[195:227])
+	assert.Equal(t, []byte("data"), encoded[227:])
+
+	// TEST 2: input is not modified
+	assert.Equal(t, owner3, sigs[0].Owner)
+	assert.Equal(t, make([]byte, 32), sigs[1].Static[32:64])
+
+}
@@ -0,0 +1,151 @@
+package gnosis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const MAX_ERROR_BODY_LENGTH = 256
+
+var (
+	ErrNonceConflict    = errors.New("nonce conflict")
+	ErrDuplicateTx      = errors.New("duplicate tx")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrNotFound         = errors.New("not found")
+	ErrRateLimited      = errors.New("rate limited")
+)
+
+// APIError is a non-2xx response of gnosis safe API
+type APIError struct {
+	StatusCode int
+	Messages   []string
+	// Err is one of the typed errors above, or nil if the response was not recognized
+	Err error
+}
+
+func (e *APIError) Error() string {
+
+	msg := fmt.Sprintf("gnosis safe api: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+
+	if len(e.Messages) > 0 {
+		msg += ": " + strings.Join(e.Messages, "; ")
+	}
+
+	return msg
+
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Temporary reports whether the request may succeed if retried
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// parseAPIError parses error response body of any format returned by gnosis safe API:
+// {"nonFieldErrors": [...]}, {"detail": "..."}, {"code": 1, "message": "...", "arguments": [...]},
+// {"field": ["..."]} or plain text
+func parseAPIError(statusCode int, body []byte) *APIError {
+
+	e := &APIError{StatusCode: statusCode}
+	e.Messages = parseErrorMessages(body)
+	e.Err = classifyAPIError(statusCode, e.Messages)
+
+	return e
+
+}
+
+func parseErrorMessages(body []byte) []string {
+
+	var resp interface{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		text := strings.TrimSpace(string(body))
+		if text == "" {
+			return nil
+		}
+		if len(text) > MAX_ERROR_BODY_LENGTH {
+			text = text[:MAX_ERROR_BODY_LENGTH] + "..."
+		}
+		return []string{text}
+	}
+
+	return flattenErrorMessages("", resp)
+
+}
+
+func flattenErrorMessages(field string, value interface{}) []string {
+
+	var msgs []string
+
+	switch v := value.(type) {
+	case string:
+		if field != "" {
+			return []string{field + ": " + v}
+		}
+		return []string{v}
+	case []interface{}:
+		for _, item := range v {
+			msgs = append(msgs, flattenErrorMessages(field, item)...)
+		}
+	case map[string]interface{}:
+		// {"code": 1, "message": "...", "arguments": [...]}
+		if message, ok := v["message"].(string); ok {
+			if args, ok := v["arguments"].([]interface{}); ok && len(args) > 0 {
+				for _, arg := range args {
+					message += " " + fmt.Sprint(arg)
+				}
+			}
+			return flattenErrorMessages(field, message)
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			switch key {
+			case "nonFieldErrors", "non_field_errors", "detail":
+				msgs = append(msgs, flattenErrorMessages(field, v[key])...)
+			default:
+				msgs = append(msgs, flattenErrorMessages(key, v[key])...)
+			}
+		}
+	case nil:
+	default:
+		return flattenErrorMessages(field, fmt.Sprint(v))
+	}
+
+	return msgs
+
+}
+
+func classifyAPIError(statusCode int, messages []string) error {
+
+	switch statusCode {
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusNotFound:
+		return ErrNotFound
+	}
+
+	for _, msg := range messages {
+		m := strings.ToLower(msg)
+		switch {
+		case strings.Contains(m, "already exists"), strings.Contains(m, "already signed"), strings.Contains(m, "duplicate"):
+			return ErrDuplicateTx
+		case strings.Contains(m, "nonce"):
+			return ErrNonceConflict
+		case strings.Contains(m, "signature"), strings.Contains(m, "not an owner"), strings.Contains(m, "not valid signer"):
+			return ErrInvalidSignature
+		}
+	}
+
+	return nil
+
+}
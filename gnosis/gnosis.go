@@ -3,29 +3,32 @@ package gnosis
 import (
 	"crypto/ecdsa"
 	"fmt"
-	"strconv"
+	"net/url"
+	"strings"
 
 	"github.com/AccumulateNetwork/bridge/config"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 const (
-	GNOSIS_API_MAINNET   = "https://safe-transaction-mainnet.safe.global/api/v1/"
-	GNOSIS_API_GOERLI    = "https://safe-transaction-goerli.safe.global/api/v1/"
-	GNOSIS_API_ARBITRUM  = "https://safe-transaction-arbitrum.safe.global/api/v1/"
-	GNOSIS_API_BNB_CHAIN = "https://safe-transaction-bsc.safe.global/api/v1/"
-	GNOSIS_API_BASE      = "https://safe-transaction-base.safe.global/api/v1/"
+	TRANSPORT_SAFE_API   = "safe"       // signatures are exchanged via Safe Transaction Service
+	TRANSPORT_ACCUMULATE = "accumulate" // signatures are exchanged via Accumulate signatures data account
+	TRANSPORT_BOTH       = "both"       // signatures are published to and gathered from both
 )
 
 type Gnosis struct {
 	API           string
+	APIKey        string
 	ChainId       int
 	SafeAddress   string
 	BridgeAddress string
 	PrivateKey    *ecdsa.PrivateKey
 	PublicKey     common.Address
+	Client        ChainReader // EVM node to read safe state from the chain
+	Transport     string      // signature transport
 }
 
 // NewGnosis constructs the gnosis safe
@@ -35,28 +38,42 @@ func NewGnosis(conf *config.Config) (*Gnosis, error) {
 
 	g.ChainId = conf.EVM.ChainId
 
-	switch g.ChainId {
-
-	case 1:
-		g.API = GNOSIS_API_MAINNET
-	case 5:
-		g.API = GNOSIS_API_GOERLI
-	case 56:
-		g.API = GNOSIS_API_BNB_CHAIN
-	case 8453:
-		g.API = GNOSIS_API_BASE
-	case 42161:
-		g.API = GNOSIS_API_ARBITRUM
-	default:
-		return nil, fmt.Errorf("received unknown chainId from config: %s", strconv.Itoa(g.ChainId))
+	profile, err := conf.GetChainProfile()
+	if err != nil {
+		return nil, err
+	}
 
+	if profile.SafeAPI == "" {
+		return nil, fmt.Errorf("received empty safeAPI from chain profile: %d", g.ChainId)
 	}
+	g.API, err = NormalizeAPI(profile.SafeAPI)
+	if err != nil {
+		return nil, err
+	}
+	g.APIKey = profile.SafeAPIKey
 
 	if conf.EVM.SafeAddress == "" {
 		return nil, fmt.Errorf("received empty safeAddress from config: %s", conf.EVM.SafeAddress)
 	}
 	g.SafeAddress = conf.EVM.SafeAddress
 
+	switch conf.EVM.SignatureTransport {
+	case TRANSPORT_SAFE_API, TRANSPORT_ACCUMULATE, TRANSPORT_BOTH:
+		g.Transport = conf.EVM.SignatureTransport
+	default:
+		return nil, fmt.Errorf("signatureTransport from config should be %s, %s or %s, received %s", TRANSPORT_SAFE_API, TRANSPORT_ACCUMULATE, TRANSPORT_BOTH, conf.EVM.SignatureTransport)
+	}
+
+	if conf.EVM.Node == "" {
+		return nil, fmt.Errorf("received empty node from config: %s", conf.EVM.Node)
+	}
+
+	client, err := ethclient.Dial(conf.EVM.Node)
+	if err != nil {
+		return nil, fmt.Errorf("can not connect to node: %s", conf.EVM.Node)
+	}
+	g.Client = client
+
 	if conf.EVM.BridgeAddress == "" {
 		return nil, fmt.Errorf("received empty bridgeAddress from config: %s", conf.EVM.BridgeAddress)
 	}
@@ -66,7 +83,7 @@ func NewGnosis(conf *config.Config) (*Gnosis, error) {
 		return nil, fmt.Errorf("received empty privateKey from config: %s", conf.EVM.PrivateKey)
 	}
 
-	g, err := g.ImportPrivateKey(conf.EVM.PrivateKey)
+	g, err = g.ImportPrivateKey(conf.EVM.PrivateKey)
 	if err != nil {
 		return nil, err
 	}
@@ -75,6 +92,16 @@ func NewGnosis(conf *config.Config) (*Gnosis, error) {
 
 }
 
+// UsesSafeAPI returns true if signatures are exchanged via Safe Transaction Service
+func (g *Gnosis) UsesSafeAPI() bool {
+	return g.Transport != TRANSPORT_ACCUMULATE
+}
+
+// UsesAccumulate returns true if signatures are exchanged via Accumulate signatures data account
+func (g *Gnosis) UsesAccumulate() bool {
+	return g.Transport == TRANSPORT_ACCUMULATE || g.Transport == TRANSPORT_BOTH
+}
+
 // ImportPrivateKey imports private key and generates corresponding public key
 func (g *Gnosis) ImportPrivateKey(pk string) (*Gnosis, error) {
 
@@ -96,3 +123,36 @@ func (g *Gnosis) ImportPrivateKey(pk string) (*Gnosis, error) {
 	return g, nil
 
 }
+
+// NormalizeAPI validates Safe Transaction Service URL and brings it to the form of API base path
+// ending with slash; for URL without path (e.g. self-hosted service) default api/v1/ path is used
+func NormalizeAPI(api string) (string, error) {
+
+	u, err := url.Parse(api)
+	if err != nil {
+		return "", fmt.Errorf("invalid safeAPI %s: %s", api, err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid safeAPI %s: expected http or https URL", api)
+	}
+
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid safeAPI %s: empty host", api)
+	}
+
+	if u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("invalid safeAPI %s: query and fragment are not allowed", api)
+	}
+
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/api/v1/"
+	}
+
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
+	}
+
+	return u.String(), nil
+
+}
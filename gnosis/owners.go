@@ -0,0 +1,387 @@
+package gnosis
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// safe owner management actions, executed by the safe on itself
+const (
+	OWNER_ADD              = "add-owner"        // addOwnerWithThreshold(owner, threshold)
+	OWNER_REMOVE           = "remove-owner"     // removeOwner(prevOwner, owner, threshold)
+	OWNER_SWAP             = "swap-owner"       // swapOwner(prevOwner, oldOwner, newOwner)
+	OWNER_CHANGE_THRESHOLD = "change-threshold" // changeThreshold(threshold)
+)
+
+// SENTINEL_OWNERS is the head of safe owners linked list, previous owner of the first owner
+const SENTINEL_OWNERS = "0x0000000000000000000000000000000000000001"
+
+// OwnerAction is change of safe owners or threshold
+type OwnerAction struct {
+	Action    string
+	Owner     string // owner to add, remove or replace
+	NewOwner  string // only for swap
+	Threshold int64  // new threshold, not used for swap
+}
+
+func (a *OwnerAction) String() string {
+	switch a.Action {
+	case OWNER_SWAP:
+		return fmt.Sprintf("%s owner=%s newOwner=%s", a.Action, a.Owner, a.NewOwner)
+	case OWNER_CHANGE_THRESHOLD:
+		return fmt.Sprintf("%s threshold=%d", a.Action, a.Threshold)
+	}
+	return fmt.Sprintf("%s owner=%s threshold=%d", a.Action, a.Owner, a.Threshold)
+}
+
+// OwnerChange is effect of owner action on safe owners and threshold
+type OwnerChange struct {
+	Owners       []common.Address
+	Threshold    int64
+	NewOwners    []common.Address
+	NewThreshold int64
+}
+
+func (c *OwnerChange) String() string {
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "threshold: %d of %d -> %d of %d\n", c.Threshold, len(c.Owners), c.NewThreshold, len(c.NewOwners))
+	fmt.Fprintln(&b, "owners:")
+
+	for _, owner := range c.NewOwners {
+		mark := " "
+		if !containsAddress(c.Owners, owner) {
+			mark = "+"
+		}
+		fmt.Fprintf(&b, "  %s %s\n", mark, owner.Hex())
+	}
+	for _, owner := range c.Owners {
+		if !containsAddress(c.NewOwners, owner) {
+			fmt.Fprintf(&b, "  - %s\n", owner.Hex())
+		}
+	}
+
+	return b.String()
+
+}
+
+// ParseOwnerAction parses owner action from command line args:
+// add-owner [owner] [threshold], remove-owner [owner] [threshold], swap-owner [owner] [new owner], change-threshold [threshold]
+func ParseOwnerAction(action string, args []string) (*OwnerAction, error) {
+
+	a := &OwnerAction{Action: action}
+
+	expected := 2
+	if action == OWNER_CHANGE_THRESHOLD {
+		expected = 1
+	}
+
+	if len(args) != expected {
+		return nil, fmt.Errorf("%s expects %d args, got %d", action, expected, len(args))
+	}
+
+	var err error
+
+	switch action {
+	case OWNER_ADD, OWNER_REMOVE:
+		a.Owner = args[0]
+		a.Threshold, err = strconv.ParseInt(args[1], 10, 64)
+	case OWNER_SWAP:
+		a.Owner = args[0]
+		a.NewOwner = args[1]
+	case OWNER_CHANGE_THRESHOLD:
+		a.Threshold, err = strconv.ParseInt(args[0], 10, 64)
+	default:
+		return nil, fmt.Errorf("unknown owner action %s", action)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid threshold: %s", err)
+	}
+
+	return a, nil
+
+}
+
+// PrepareOwnerTx validates owner action against the safe state and generates safe tx at the current safe nonce
+func (g *Gnosis) PrepareOwnerTx(safe *SafeState, action *OwnerAction) (*SafeTx, *OwnerChange, error) {
+
+	change, err := ApplyOwnerAction(safe, action)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	data, err := ownerTxData(safe, action)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return g.NewSafeTx(g.SafeAddress, data, safe.Nonce), change, nil
+
+}
+
+// ConfirmOwnerTx co-signs proposed owner safe tx after verifying that it is the decoded owner action
+func (g *Gnosis) ConfirmOwnerTx(safe *SafeState, tx *MultisigTx) (*OwnerAction, error) {
+
+	action, _, err := g.DecodeOwnerTx(safe, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ownerTxData(safe, action)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = g.ProposeSafeTx(g.NewSafeTx(g.SafeAddress, data, tx.Nonce), tx.SafeTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return action, nil
+
+}
+
+// DecodeOwnerTx decodes proposed safe tx into owner action and its effect on the current safe state,
+// returns error if tx is not an owner action or can not be applied to the safe
+func (g *Gnosis) DecodeOwnerTx(safe *SafeState, tx *MultisigTx) (*OwnerAction, *OwnerChange, error) {
+
+	if !strings.EqualFold(tx.To, g.SafeAddress) {
+		return nil, nil, fmt.Errorf("owner tx should be a call of the safe, got %s", tx.To)
+	}
+
+	if tx.Value != 0 || tx.Operation != OPERATION_CALL {
+		return nil, nil, fmt.Errorf("owner tx should be a call without value")
+	}
+
+	data, err := hexutil.Decode(tx.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(data) < 4 {
+		return nil, nil, fmt.Errorf("tx data is too short")
+	}
+
+	safeABI, err := abiutil.NewABI([]byte(abiutil.GNOSIS_ABI))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	method, err := safeABI.MethodById(data[:4])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	action := &OwnerAction{}
+
+	switch method.Name {
+	case "addOwnerWithThreshold":
+		action.Action = OWNER_ADD
+		action.Owner = args[0].(common.Address).Hex()
+		action.Threshold, err = thresholdArg(args[1])
+	case "removeOwner":
+		action.Action = OWNER_REMOVE
+		action.Owner = args[1].(common.Address).Hex()
+		action.Threshold, err = thresholdArg(args[2])
+	case "swapOwner":
+		action.Action = OWNER_SWAP
+		action.Owner = args[1].(common.Address).Hex()
+		action.NewOwner = args[2].(common.Address).Hex()
+	case "changeThreshold":
+		action.Action = OWNER_CHANGE_THRESHOLD
+		action.Threshold, err = thresholdArg(args[0])
+	default:
+		return nil, nil, fmt.Errorf("method %s of the safe is not an owner action", method.Name)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	change, err := ApplyOwnerAction(safe, action)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// data should be exactly the encoded action, including previous owner in the current owners list
+	expected, err := ownerTxData(safe, action)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !bytes.Equal(data, expected) {
+		return nil, nil, fmt.Errorf("tx data does not match encoded %s", action)
+	}
+
+	return action, change, nil
+
+}
+
+// ApplyOwnerAction validates owner action the same way the safe does and returns its effect on owners and threshold
+func ApplyOwnerAction(safe *SafeState, action *OwnerAction) (*OwnerChange, error) {
+
+	change := &OwnerChange{
+		Owners:       safe.Owners,
+		Threshold:    safe.Threshold,
+		NewThreshold: safe.Threshold,
+	}
+
+	switch action.Action {
+	case OWNER_ADD:
+		owner, err := newOwnerAddress(safe, action.Owner)
+		if err != nil {
+			return nil, err
+		}
+		// safe inserts new owner at the head of the list
+		change.NewOwners = append([]common.Address{owner}, safe.Owners...)
+		change.NewThreshold = action.Threshold
+	case OWNER_REMOVE:
+		owner, err := ownerAddress(safe, action.Owner)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range safe.Owners {
+			if o != owner {
+				change.NewOwners = append(change.NewOwners, o)
+			}
+		}
+		change.NewThreshold = action.Threshold
+	case OWNER_SWAP:
+		owner, err := ownerAddress(safe, action.Owner)
+		if err != nil {
+			return nil, err
+		}
+		newOwner, err := newOwnerAddress(safe, action.NewOwner)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range safe.Owners {
+			if o == owner {
+				o = newOwner
+			}
+			change.NewOwners = append(change.NewOwners, o)
+		}
+	case OWNER_CHANGE_THRESHOLD:
+		change.NewOwners = safe.Owners
+		change.NewThreshold = action.Threshold
+	default:
+		return nil, fmt.Errorf("unknown owner action %s", action.Action)
+	}
+
+	if change.NewThreshold < 1 || change.NewThreshold > int64(len(change.NewOwners)) {
+		return nil, fmt.Errorf("threshold %d is out of range for %d owners", change.NewThreshold, len(change.NewOwners))
+	}
+
+	return change, nil
+
+}
+
+// ownerTxData generates data of safe call for owner action, previous owners are taken from the safe state
+func ownerTxData(safe *SafeState, action *OwnerAction) ([]byte, error) {
+
+	safeABI, err := abiutil.NewABI([]byte(abiutil.GNOSIS_ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := big.NewInt(action.Threshold)
+
+	switch action.Action {
+	case OWNER_ADD:
+		return safeABI.Pack("addOwnerWithThreshold", common.HexToAddress(action.Owner), threshold)
+	case OWNER_REMOVE:
+		owner := common.HexToAddress(action.Owner)
+		return safeABI.Pack("removeOwner", prevOwner(safe, owner), owner, threshold)
+	case OWNER_SWAP:
+		owner := common.HexToAddress(action.Owner)
+		return safeABI.Pack("swapOwner", prevOwner(safe, owner), owner, common.HexToAddress(action.NewOwner))
+	case OWNER_CHANGE_THRESHOLD:
+		return safeABI.Pack("changeThreshold", threshold)
+	}
+
+	return nil, fmt.Errorf("unknown owner action %s", action.Action)
+
+}
+
+// prevOwner returns previous owner in the safe owners linked list
+func prevOwner(safe *SafeState, owner common.Address) common.Address {
+
+	prev := common.HexToAddress(SENTINEL_OWNERS)
+
+	for _, o := range safe.Owners {
+		if o == owner {
+			break
+		}
+		prev = o
+	}
+
+	return prev
+
+}
+
+func ownerAddress(safe *SafeState, address string) (common.Address, error) {
+
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("invalid owner address %s", address)
+	}
+
+	owner := common.HexToAddress(address)
+	if !safe.IsOwner(owner) {
+		return common.Address{}, fmt.Errorf("%s is not a safe owner", owner.Hex())
+	}
+
+	return owner, nil
+
+}
+
+func newOwnerAddress(safe *SafeState, address string) (common.Address, error) {
+
+	if !common.IsHexAddress(address) {
+		return common.Address{}, fmt.Errorf("invalid owner address %s", address)
+	}
+
+	owner := common.HexToAddress(address)
+
+	if owner == (common.Address{}) || owner == common.HexToAddress(SENTINEL_OWNERS) {
+		return common.Address{}, fmt.Errorf("invalid owner address %s", owner.Hex())
+	}
+
+	if safe.IsOwner(owner) {
+		return common.Address{}, fmt.Errorf("%s is already a safe owner", owner.Hex())
+	}
+
+	return owner, nil
+
+}
+
+func thresholdArg(arg interface{}) (int64, error) {
+
+	threshold, ok := arg.(*big.Int)
+	if !ok || !threshold.IsInt64() {
+		return 0, fmt.Errorf("invalid threshold %v", arg)
+	}
+
+	return threshold.Int64(), nil
+
+}
+
+func containsAddress(list []common.Address, address common.Address) bool {
+	for _, a := range list {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
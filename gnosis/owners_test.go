@@ -0,0 +1,84 @@
+package gnosis
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnerActions(t *testing.T) {
+
+	g := &Gnosis{ChainId: 5, SafeAddress: "0x24BbA5D6fD7fC2Cbc293FDa6721c9BE6756D177a", BridgeAddress: "0x903f0dA0697FC1c81ecACc83b2A7445F392399e8"}
+
+	owner1 := common.HexToAddress("0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314")
+	owner2 := common.HexToAddress("0x4E780D102AADECF1BdC06d91542cf91960538a2D")
+	newOwner := common.HexToAddress("0x903f0dA0697FC1c81ecACc83b2A7445F392399e8")
+
+	safe := &SafeState{Nonce: 7, Threshold: 1, Owners: []common.Address{owner1, owner2}}
+
+	tests := []struct {
+		action       *OwnerAction
+		newOwners    []common.Address
+		newThreshold int64
+	}{
+		{&OwnerAction{Action: OWNER_ADD, Owner: newOwner.Hex(), Threshold: 2}, []common.Address{newOwner, owner1, owner2}, 2},
+		{&OwnerAction{Action: OWNER_REMOVE, Owner: owner2.Hex(), Threshold: 1}, []common.Address{owner1}, 1},
+		{&OwnerAction{Action: OWNER_SWAP, Owner: owner1.Hex(), NewOwner: newOwner.Hex()}, []common.Address{newOwner, owner2}, 1},
+		{&OwnerAction{Action: OWNER_CHANGE_THRESHOLD, Threshold: 2}, []common.Address{owner1, owner2}, 2},
+	}
+
+	// TEST 1: proposed actions are decoded back with their effect
+	for _, test := range tests {
+		safeTx, change, err := g.PrepareOwnerTx(safe, test.action)
+		assert.NoError(t, err)
+		assert.Equal(t, common.HexToAddress(g.SafeAddress), safeTx.To)
+		assert.Equal(t, int64(7), safeTx.Nonce)
+		assert.Equal(t, test.newOwners, change.NewOwners)
+		assert.Equal(t, test.newThreshold, change.NewThreshold)
+
+		tx := &MultisigTx{To: g.SafeAddress, Data: hexutil.Encode(safeTx.Data), Nonce: 7}
+		decoded, decodedChange, err := g.DecodeOwnerTx(safe, tx)
+		assert.NoError(t, err)
+		assert.Equal(t, test.action, decoded)
+		assert.Equal(t, change, decodedChange)
+	}
+
+	// TEST 2: invalid actions
+	invalid := []*OwnerAction{
+		{Action: OWNER_ADD, Owner: owner1.Hex(), Threshold: 1},            // already an owner
+		{Action: OWNER_ADD, Owner: SENTINEL_OWNERS, Threshold: 1},         // sentinel
+		{Action: OWNER_ADD, Owner: newOwner.Hex(), Threshold: 4},          // threshold too high
+		{Action: OWNER_REMOVE, Owner: newOwner.Hex(), Threshold: 1},       // not an owner
+		{Action: OWNER_REMOVE, Owner: owner1.Hex(), Threshold: 2},         // threshold too high
+		{Action: OWNER_SWAP, Owner: owner1.Hex(), NewOwner: owner2.Hex()}, // already an owner
+		{Action: OWNER_CHANGE_THRESHOLD, Threshold: 0},
+		{Action: "unknown"},
+	}
+	for _, action := range invalid {
+		_, _, err := g.PrepareOwnerTx(safe, action)
+		assert.Error(t, err, action.String())
+	}
+
+	// TEST 3: previous owner does not match the current owners list
+	safeTx, _, err := g.PrepareOwnerTx(safe, tests[1].action)
+	assert.NoError(t, err)
+	reordered := &SafeState{Nonce: 7, Threshold: 1, Owners: []common.Address{owner2, owner1}}
+	_, _, err = g.DecodeOwnerTx(reordered, &MultisigTx{To: g.SafeAddress, Data: hexutil.Encode(safeTx.Data), Nonce: 7})
+	assert.Error(t, err)
+
+	// TEST 4: owner action of another contract
+	_, _, err = g.DecodeOwnerTx(safe, &MultisigTx{To: g.BridgeAddress, Data: hexutil.Encode(safeTx.Data), Nonce: 7})
+	assert.Error(t, err)
+
+	// TEST 5: command line args
+	action, err := ParseOwnerAction(OWNER_ADD, []string{newOwner.Hex(), "2"})
+	assert.NoError(t, err)
+	assert.Equal(t, tests[0].action, action)
+	_, err = ParseOwnerAction(OWNER_CHANGE_THRESHOLD, []string{"2", "3"})
+	assert.Error(t, err)
+	_, err = ParseOwnerAction(OWNER_REMOVE, []string{owner1.Hex(), "two"})
+	assert.Error(t, err)
+
+}
@@ -0,0 +1,85 @@
+package gnosis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+)
+
+// ErrExecFailed is returned if execTransaction does not revert, but the safe tx call fails (returns false)
+var ErrExecFailed = errors.New("safe tx call failed")
+
+// SAFE_ERRORS are gnosis safe v1.3.0 revert codes
+var SAFE_ERRORS = map[string]string{
+	"GS000": "could not finish initialization",
+	"GS001": "threshold needs to be defined",
+	"GS010": "not enough gas to execute safe transaction",
+	"GS011": "could not pay gas costs with ether",
+	"GS012": "could not pay gas costs with token",
+	"GS013": "safe transaction failed when gasPrice and safeTxGas were 0",
+	"GS020": "signatures data too short",
+	"GS021": "invalid contract signature location: inside static part",
+	"GS022": "invalid contract signature location: length not present",
+	"GS023": "invalid contract signature location: data not complete",
+	"GS024": "invalid contract signature provided",
+	"GS025": "hash has not been approved",
+	"GS026": "invalid owner provided",
+	"GS030": "only owners can approve a hash",
+	"GS031": "method can only be called from this contract",
+	"GS100": "modules have already been initialized",
+	"GS101": "invalid module address provided",
+	"GS102": "module has already been added",
+	"GS103": "invalid prevModule, module pair provided",
+	"GS104": "method can only be called from an enabled module",
+	"GS200": "owners have already been setup",
+	"GS201": "threshold cannot exceed owner count",
+	"GS202": "threshold needs to be greater than 0",
+	"GS203": "invalid owner address provided",
+	"GS204": "address is already an owner",
+	"GS205": "invalid prevOwner, owner pair provided",
+	"GS300": "guard does not implement IERC165",
+}
+
+var safeErrorCode = regexp.MustCompile(`GS\d{3}`)
+
+// DescribeRevert appends description of gnosis safe error code to revert error, if the code is found
+func DescribeRevert(err error) string {
+
+	code := safeErrorCode.FindString(err.Error())
+
+	if description, ok := SAFE_ERRORS[code]; ok {
+		return fmt.Sprintf("%s (%s)", err, description)
+	}
+
+	return err.Error()
+
+}
+
+// CheckExecResult checks output of simulated execTransaction. With non-zero safeTxGas or gasPrice
+// the safe does not revert if the call fails, but returns false
+func CheckExecResult(output []byte) error {
+
+	safeABI, err := abiutil.NewABI([]byte(abiutil.GNOSIS_ABI))
+	if err != nil {
+		return err
+	}
+
+	out, err := safeABI.Unpack("execTransaction", output)
+	if err != nil {
+		return fmt.Errorf("can not decode execTransaction result: %s", err)
+	}
+
+	success, ok := out[0].(bool)
+	if !ok {
+		return fmt.Errorf("unexpected execTransaction result")
+	}
+
+	if !success {
+		return ErrExecFailed
+	}
+
+	return nil
+
+}
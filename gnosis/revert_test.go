@@ -0,0 +1,36 @@
+package gnosis
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeExecErrors(t *testing.T) {
+
+	// TEST 1: safe error code is described
+	assert.Equal(t, "execution reverted: GS026 (invalid owner provided)", DescribeRevert(errors.New("execution reverted: GS026")))
+
+	// TEST 2: other reasons are not changed
+	assert.Equal(t, "execution reverted: Pausable: paused", DescribeRevert(errors.New("execution reverted: Pausable: paused")))
+	assert.Equal(t, "execution reverted: GS999", DescribeRevert(errors.New("execution reverted: GS999")))
+
+	safeABI, err := abiutil.NewABI([]byte(abiutil.GNOSIS_ABI))
+	assert.NoError(t, err)
+
+	// TEST 3: execTransaction returned true
+	out, err := safeABI.Methods["execTransaction"].Outputs.Pack(true)
+	assert.NoError(t, err)
+	assert.NoError(t, CheckExecResult(out))
+
+	// TEST 4: safe tx call failed without revert
+	out, err = safeABI.Methods["execTransaction"].Outputs.Pack(false)
+	assert.NoError(t, err)
+	assert.True(t, errors.Is(CheckExecResult(out), ErrExecFailed))
+
+	// TEST 5: invalid output
+	assert.Error(t, CheckExecResult([]byte{1}))
+
+}
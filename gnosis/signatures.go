@@ -0,0 +1,137 @@
+package gnosis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const SIGNATURE_TYPE_EOA = "EOA"
+
+// RecoverSigner recovers address that signed gnosis safe call of contract `to` with data at the given nonce,
+// returns safe tx hash and the signer
+func (g *Gnosis) RecoverSigner(to string, data []byte, nonce int64, signature []byte) ([]byte, common.Address, error) {
+
+	hash, err := g.NewSafeTx(to, data, nonce).Hash()
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	if len(signature) != crypto.SignatureLength {
+		return nil, common.Address{}, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, signature)
+
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	if sig[64] > 1 {
+		return nil, common.Address{}, fmt.Errorf("unsupported signature v=%d", signature[64])
+	}
+
+	pubKey, err := crypto.SigToPub(hash, sig)
+	if err != nil {
+		return nil, common.Address{}, err
+	}
+
+	return hash, crypto.PubkeyToAddress(*pubKey), nil
+
+}
+
+// VerifySignature verifies that signature of safe tx published outside of gnosis safe API is made by a safe owner,
+// returns the tx with a single confirmation
+func (g *Gnosis) VerifySignature(safe *SafeState, to string, data string, nonce int64, safeTxHash string, signature string) (*MultisigTx, error) {
+
+	dataBytes, err := hexutil.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("can not decode data: %s", err)
+	}
+
+	sigBytes, err := hexutil.Decode(signature)
+	if err != nil {
+		return nil, fmt.Errorf("can not decode signature: %s", err)
+	}
+
+	hash, signer, err := g.RecoverSigner(to, dataBytes, nonce, sigBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	if hexutil.Encode(hash) != strings.ToLower(safeTxHash) {
+		return nil, fmt.Errorf("safe tx hash %s does not match signed tx %s", safeTxHash, hexutil.Encode(hash))
+	}
+
+	if !safe.IsOwner(signer) {
+		return nil, fmt.Errorf("signer %s is not a safe owner", signer.Hex())
+	}
+
+	tx := &MultisigTx{
+		Safe:       g.SafeAddress,
+		To:         to,
+		Data:       data,
+		Nonce:      nonce,
+		SafeTxHash: hexutil.Encode(hash),
+		Confirmations: []*MultisigTxConfirmation{
+			{Owner: signer.Hex(), Signature: signature, SignatureType: SIGNATURE_TYPE_EOA},
+		},
+	}
+
+	return tx, nil
+
+}
+
+// HasConfirmation checks if tx is confirmed by the owner
+func (tx *MultisigTx) HasConfirmation(owner common.Address) bool {
+
+	for _, con := range tx.Confirmations {
+		if strings.EqualFold(con.Owner, owner.Hex()) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// MergeMultisigTxs merges lists of txs from different sources, txs with the same safeTxHash are merged into one,
+// confirmations of the same owner are deduplicated. Input txs are not modified
+func MergeMultisigTxs(lists ...[]*MultisigTx) []*MultisigTx {
+
+	var merged []*MultisigTx
+	byHash := make(map[string]*MultisigTx)
+
+	for _, list := range lists {
+		for _, tx := range list {
+
+			hash := strings.ToLower(tx.SafeTxHash)
+
+			m, ok := byHash[hash]
+			if !ok {
+				txCopy := *tx
+				txCopy.Confirmations = nil
+				m = &txCopy
+				byHash[hash] = m
+				merged = append(merged, m)
+			}
+
+			m.IsExecuted = m.IsExecuted || tx.IsExecuted
+
+			for _, con := range tx.Confirmations {
+				if !m.HasConfirmation(common.HexToAddress(con.Owner)) {
+					m.Confirmations = append(m.Confirmations, con)
+				}
+			}
+
+		}
+	}
+
+	sortMultisigTxs(merged)
+
+	return merged
+
+}
@@ -0,0 +1,98 @@
+package gnosis
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySignature(t *testing.T) {
+
+	g := &Gnosis{ChainId: 5, SafeAddress: "0x24BbA5D6fD7fC2Cbc293FDa6721c9BE6756D177a", BridgeAddress: "0x903f0dA0697FC1c81ecACc83b2A7445F392399e8"}
+	_, err := g.ImportPrivateKey("08108aadbbe82e9ffa1eba54158e7aacbec6115156242558ae7e594037220e4a")
+	assert.NoError(t, err)
+
+	safe := &SafeState{Nonce: 7, Threshold: 1, Owners: []common.Address{g.PublicKey}}
+
+	data, err := abiutil.GenerateMintTxData("0x4E780D102AADECF1BdC06d91542cf91960538a2D", "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314", big.NewInt(1e8))
+	assert.NoError(t, err)
+
+	hash, signature, err := g.NewSafeTx(g.BridgeAddress, data, 7).Sign(g.PrivateKey)
+	assert.NoError(t, err)
+
+	// TEST 1: valid signature
+	tx, err := g.VerifySignature(safe, g.BridgeAddress, hexutil.Encode(data), 7, hexutil.Encode(hash), hexutil.Encode(signature))
+	assert.NoError(t, err)
+	assert.Equal(t, hexutil.Encode(hash), tx.SafeTxHash)
+	assert.Equal(t, int64(7), tx.Nonce)
+	assert.True(t, tx.HasConfirmation(g.PublicKey))
+
+	// TEST 2: signature with v = 0/1
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+	sig[64] -= 27
+	_, signer, err := g.RecoverSigner(g.BridgeAddress, data, 7, sig)
+	assert.NoError(t, err)
+	assert.Equal(t, g.PublicKey, signer)
+
+	// TEST 3: different nonce
+	_, err = g.VerifySignature(safe, g.BridgeAddress, hexutil.Encode(data), 8, hexutil.Encode(hash), hexutil.Encode(signature))
+	assert.Error(t, err)
+
+	// TEST 4: different data
+	otherData, err := abiutil.GenerateMintTxData("0x4E780D102AADECF1BdC06d91542cf91960538a2D", "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314", big.NewInt(2e8))
+	assert.NoError(t, err)
+	_, err = g.VerifySignature(safe, g.BridgeAddress, hexutil.Encode(otherData), 7, hexutil.Encode(hash), hexutil.Encode(signature))
+	assert.Error(t, err)
+
+	// TEST 5: signer is not an owner
+	notOwner := &SafeState{Nonce: 7, Threshold: 1, Owners: []common.Address{common.HexToAddress("0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314")}}
+	_, err = g.VerifySignature(notOwner, g.BridgeAddress, hexutil.Encode(data), 7, hexutil.Encode(hash), hexutil.Encode(signature))
+	assert.Error(t, err)
+
+	// TEST 6: invalid signature
+	_, err = g.VerifySignature(safe, g.BridgeAddress, hexutil.Encode(data), 7, hexutil.Encode(hash), hexutil.Encode(signature[:64]))
+	assert.Error(t, err)
+	sig[64] = 31
+	_, _, err = g.RecoverSigner(g.BridgeAddress, data, 7, sig)
+	assert.Error(t, err)
+
+}
+
+func TestMergeSignedSafeTxs(t *testing.T) {
+
+	owner1 := "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"
+	owner2 := "0x4E780D102AADECF1BdC06d91542cf91960538a2D"
+
+	api := []*MultisigTx{
+		{SafeTxHash: "0x02", Nonce: 6, Confirmations: []*MultisigTxConfirmation{{Owner: owner1, Signature: "0x01"}}},
+		{SafeTxHash: "0x01", Nonce: 5, Confirmations: []*MultisigTxConfirmation{{Owner: owner1, Signature: "0x01"}}},
+	}
+
+	acc := []*MultisigTx{
+		{SafeTxHash: "0x01", Nonce: 5, Confirmations: []*MultisigTxConfirmation{{Owner: owner2, Signature: "0x02"}}},
+		{SafeTxHash: "0x01", Nonce: 5, Confirmations: []*MultisigTxConfirmation{{Owner: "0xc6386b0a95b60bcea480c876e3b1f9adb5b85314", Signature: "0x01"}}},
+		{SafeTxHash: "0x03", Nonce: 5, Confirmations: []*MultisigTxConfirmation{{Owner: owner2, Signature: "0x03"}}},
+	}
+
+	merged := MergeMultisigTxs(api, acc)
+
+	// TEST 1: txs are merged by safeTxHash and sorted by nonce
+	assert.Len(t, merged, 3)
+	assert.Equal(t, "0x01", merged[0].SafeTxHash)
+	assert.Equal(t, "0x03", merged[1].SafeTxHash)
+	assert.Equal(t, "0x02", merged[2].SafeTxHash)
+
+	// TEST 2: confirmations are deduplicated by owner
+	assert.Len(t, merged[0].Confirmations, 2)
+	assert.True(t, merged[0].HasConfirmation(common.HexToAddress(owner1)))
+	assert.True(t, merged[0].HasConfirmation(common.HexToAddress(owner2)))
+
+	// TEST 3: inputs are not modified
+	assert.Len(t, api[1].Confirmations, 1)
+
+}
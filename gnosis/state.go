@@ -0,0 +1,173 @@
+package gnosis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const SAFE_STATE_TIMEOUT = 15 * time.Second
+
+var ErrStateMismatch = errors.New("safe state mismatch between chain and safe api")
+
+// ChainReader is the subset of EVM client used to read safe state from the chain
+type ChainReader interface {
+	bind.ContractCaller
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// SafeState is gnosis safe state read from the chain
+type SafeState struct {
+	Block     uint64
+	Nonce     int64
+	Threshold int64
+	Owners    []common.Address
+}
+
+// GetSafeState reads safe nonce, threshold and owners from the chain and cross-checks them with gnosis safe API,
+// unless signatures are exchanged via Accumulate only. If the API is unavailable, state from the chain is returned. If the API disagrees with the chain, ErrStateMismatch is returned
+func (g *Gnosis) GetSafeState() (*SafeState, error) {
+
+	state, err := g.GetSafeStateOnChain()
+	if err != nil {
+		return nil, err
+	}
+
+	if !g.UsesSafeAPI() {
+		return state, nil
+	}
+
+	safe, err := g.GetSafe()
+	if err != nil {
+		fmt.Println("[gnosis] safe api is unavailable, using safe state from the chain:", err)
+		return state, nil
+	}
+
+	if err = compareSafeState(state, safe); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+
+}
+
+// GetSafeStateOnChain reads safe nonce, threshold and owners with eth_call at the latest block
+func (g *Gnosis) GetSafeStateOnChain() (*SafeState, error) {
+
+	if g.Client == nil {
+		return nil, fmt.Errorf("evm node is not configured, can not read safe state from the chain")
+	}
+
+	safeABI, err := abiutil.NewABI([]byte(abiutil.GNOSIS_ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), SAFE_STATE_TIMEOUT)
+	defer cancel()
+
+	// all values are read at the same block
+	block, err := g.Client.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	contract := bind.NewBoundContract(common.HexToAddress(g.SafeAddress), *safeABI, g.Client, nil, nil)
+	opts := &bind.CallOpts{Context: ctx, BlockNumber: new(big.Int).SetUint64(block)}
+
+	state := &SafeState{Block: block}
+
+	var out []interface{}
+	if err = contract.Call(opts, &out, "nonce"); err != nil {
+		return nil, fmt.Errorf("can not read safe nonce: %s", err)
+	}
+	nonce := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	if !nonce.IsInt64() {
+		return nil, fmt.Errorf("safe nonce %s is out of range", nonce)
+	}
+	state.Nonce = nonce.Int64()
+
+	out = nil
+	if err = contract.Call(opts, &out, "getThreshold"); err != nil {
+		return nil, fmt.Errorf("can not read safe threshold: %s", err)
+	}
+	threshold := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+	if !threshold.IsInt64() {
+		return nil, fmt.Errorf("safe threshold %s is out of range", threshold)
+	}
+	state.Threshold = threshold.Int64()
+
+	out = nil
+	if err = contract.Call(opts, &out, "getOwners"); err != nil {
+		return nil, fmt.Errorf("can not read safe owners: %s", err)
+	}
+	state.Owners = *abi.ConvertType(out[0], new([]common.Address)).(*[]common.Address)
+
+	if state.Threshold < 1 || len(state.Owners) < int(state.Threshold) {
+		return nil, fmt.Errorf("invalid safe %s: threshold %d, %d owners", g.SafeAddress, state.Threshold, len(state.Owners))
+	}
+
+	return state, nil
+
+}
+
+// IsOwner checks if address is one of safe owners
+func (s *SafeState) IsOwner(address common.Address) bool {
+
+	for _, owner := range s.Owners {
+		if owner == address {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+// compareSafeState returns ErrStateMismatch if safe api response differs from the state read from the chain
+func compareSafeState(state *SafeState, safe *ResponseSafe) error {
+
+	nonce, err := strconv.ParseInt(safe.Nonce, 10, 64)
+	if err != nil {
+		return fmt.Errorf("can not parse safe api nonce %s: %s", safe.Nonce, err)
+	}
+
+	// safe api lagging behind the chain is also a mismatch, as its pending txs can not be trusted
+	if nonce != state.Nonce {
+		return fmt.Errorf("%w: nonce %d on chain, %d on safe api", ErrStateMismatch, state.Nonce, nonce)
+	}
+
+	if safe.Threshold != state.Threshold {
+		return fmt.Errorf("%w: threshold %d on chain, %d on safe api", ErrStateMismatch, state.Threshold, safe.Threshold)
+	}
+
+	chainOwners := make([]string, len(state.Owners))
+	for i, owner := range state.Owners {
+		chainOwners[i] = strings.ToLower(owner.Hex())
+	}
+
+	apiOwners := make([]string, len(safe.Owners))
+	for i, owner := range safe.Owners {
+		apiOwners[i] = strings.ToLower(owner)
+	}
+
+	sort.Strings(chainOwners)
+	sort.Strings(apiOwners)
+
+	if strings.Join(chainOwners, ",") != strings.Join(apiOwners, ",") {
+		return fmt.Errorf("%w: owners %v on chain, %v on safe api", ErrStateMismatch, chainOwners, apiOwners)
+	}
+
+	return nil
+
+}
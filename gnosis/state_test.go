@@ -0,0 +1,165 @@
+package gnosis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSafe returns safe state for eth_calls of nonce, getThreshold, getOwners and approvedHashes,
+// and validates signatures of contract owners
+type fakeSafe struct {
+	block        uint64
+	nonce        int64
+	threshold    int64
+	owners       []common.Address
+	approved     map[common.Address]bool
+	contractSigs map[common.Address][]byte
+	calls        []*big.Int
+}
+
+func (f *fakeSafe) BlockNumber(ctx context.Context) (uint64, error) {
+	return f.block, nil
+}
+
+func (f *fakeSafe) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (f *fakeSafe) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+
+	f.calls = append(f.calls, blockNumber)
+
+	if validSig, ok := f.contractSigs[*call.To]; ok {
+		validatorABI, err := abiutil.NewABI([]byte(abiutil.EIP1271_ABI))
+		if err != nil {
+			return nil, err
+		}
+		method := validatorABI.Methods["isValidSignature"]
+		args, err := method.Inputs.Unpack(call.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		result := [4]byte{}
+		if bytes.Equal(args[1].([]byte), validSig) {
+			copy(result[:], hexutil.MustDecode(abiutil.EIP1271_MAGIC_VALUE))
+		}
+		return method.Outputs.Pack(result)
+	}
+
+	safeABI, err := abiutil.NewABI([]byte(abiutil.GNOSIS_ABI))
+	if err != nil {
+		return nil, err
+	}
+
+	for name, method := range safeABI.Methods {
+		if !bytes.Equal(call.Data[:4], method.ID) {
+			continue
+		}
+		switch name {
+		case "nonce":
+			return method.Outputs.Pack(big.NewInt(f.nonce))
+		case "getThreshold":
+			return method.Outputs.Pack(big.NewInt(f.threshold))
+		case "getOwners":
+			return method.Outputs.Pack(f.owners)
+		case "approvedHashes":
+			args, err := method.Inputs.Unpack(call.Data[4:])
+			if err != nil {
+				return nil, err
+			}
+			if f.approved[args[0].(common.Address)] {
+				return method.Outputs.Pack(big.NewInt(1))
+			}
+			return method.Outputs.Pack(big.NewInt(0))
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected call")
+
+}
+
+func TestSafeStateOnChain(t *testing.T) {
+
+	owners := []common.Address{
+		common.HexToAddress("0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"),
+		common.HexToAddress("0x4E780D102AADECF1BdC06d91542cf91960538a2D"),
+	}
+
+	fake := &fakeSafe{block: 100, nonce: 42, threshold: 2, owners: owners}
+
+	g := &Gnosis{SafeAddress: "0x24BbA5D6fD7fC2Cbc293FDa6721c9BE6756D177a", Client: fake}
+
+	// TEST 1: state is read at the same block
+	state, err := g.GetSafeStateOnChain()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), state.Block)
+	assert.Equal(t, int64(42), state.Nonce)
+	assert.Equal(t, int64(2), state.Threshold)
+	assert.Equal(t, owners, state.Owners)
+	assert.True(t, state.IsOwner(owners[1]))
+	assert.False(t, state.IsOwner(common.HexToAddress("0x903f0dA0697FC1c81ecACc83b2A7445F392399e8")))
+	assert.Len(t, fake.calls, 3)
+	for _, block := range fake.calls {
+		assert.Equal(t, big.NewInt(100), block)
+	}
+
+	// TEST 2: threshold greater than number of owners
+	fake.threshold = 3
+	_, err = g.GetSafeStateOnChain()
+	assert.Error(t, err)
+
+	// TEST 3: no evm node
+	g.Client = nil
+	_, err = g.GetSafeStateOnChain()
+	assert.Error(t, err)
+
+}
+
+func TestCompareSafeStateOwners(t *testing.T) {
+
+	state := &SafeState{
+		Nonce:     42,
+		Threshold: 2,
+		Owners: []common.Address{
+			common.HexToAddress("0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"),
+			common.HexToAddress("0x4E780D102AADECF1BdC06d91542cf91960538a2D"),
+		},
+	}
+
+	// TEST 1: same state, owners in different order and case
+	safe := &ResponseSafe{Nonce: "42", Threshold: 2, Owners: []string{"0x4e780d102aadecf1bdc06d91542cf91960538a2d", "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"}}
+	assert.NoError(t, compareSafeState(state, safe))
+
+	// TEST 2: safe api nonce is behind
+	safe.Nonce = "41"
+	assert.True(t, errors.Is(compareSafeState(state, safe), ErrStateMismatch))
+	safe.Nonce = "42"
+
+	// TEST 3: different threshold
+	safe.Threshold = 1
+	assert.True(t, errors.Is(compareSafeState(state, safe), ErrStateMismatch))
+	safe.Threshold = 2
+
+	// TEST 4: different owners
+	safe.Owners = []string{"0x4E780D102AADECF1BdC06d91542cf91960538a2D", "0x903f0dA0697FC1c81ecACc83b2A7445F392399e8"}
+	assert.True(t, errors.Is(compareSafeState(state, safe), ErrStateMismatch))
+
+	// TEST 5: missing owner
+	safe.Owners = []string{"0x4E780D102AADECF1BdC06d91542cf91960538a2D"}
+	assert.True(t, errors.Is(compareSafeState(state, safe), ErrStateMismatch))
+
+	// TEST 6: invalid nonce
+	safe.Nonce = "invalid"
+	assert.Error(t, compareSafeState(state, safe))
+
+}
@@ -1,8 +1,10 @@
 package gnosis
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/AccumulateNetwork/bridge/abiutil"
 	"github.com/ethereum/go-ethereum/common"
@@ -12,59 +14,188 @@ import (
 	"github.com/ethereum/go-ethereum/signer/core"
 )
 
-func (g *Gnosis) SignMintTx(tokenAddress string, recipientAddress string, amount *big.Int) ([]byte, []byte, error) {
+// safe tx operations
+const (
+	OPERATION_CALL         = 0
+	OPERATION_DELEGATECALL = 1
+)
+
+// SafeTx is gnosis safe transaction of a given safe and chain, signed by owners and executed with execTransaction
+type SafeTx struct {
+	Safe           common.Address
+	ChainId        int
+	To             common.Address
+	Value          *big.Int
+	Data           []byte
+	Operation      uint8
+	SafeTxGas      *big.Int
+	BaseGas        *big.Int
+	GasPrice       *big.Int
+	GasToken       common.Address
+	RefundReceiver common.Address
+	Nonce          int64
+}
+
+// NewSafeTx creates safe call of contract `to` with data at the given nonce, without value and gas refunds
+func (g *Gnosis) NewSafeTx(to string, data []byte, nonce int64) *SafeTx {
+
+	return &SafeTx{
+		Safe:      common.HexToAddress(g.SafeAddress),
+		ChainId:   g.ChainId,
+		To:        common.HexToAddress(to),
+		Value:     big.NewInt(0),
+		Data:      data,
+		Operation: OPERATION_CALL,
+		SafeTxGas: big.NewInt(0),
+		BaseGas:   big.NewInt(0),
+		GasPrice:  big.NewInt(0),
+		Nonce:     nonce,
+	}
 
-	safe, err := g.GetSafe()
+}
+
+// NewMintTx creates safe tx at nonce, minting wrapped tokens via bridge
+func (g *Gnosis) NewMintTx(tokenAddress string, recipientAddress string, amount *big.Int, nonce int64) (*SafeTx, error) {
+
+	data, err := abiutil.GenerateMintTxData(tokenAddress, recipientAddress, amount)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	nonce := new(big.Int)
-	nonce, ok := nonce.SetString(safe.Nonce, 10)
-	if !ok {
-		return nil, nil, fmt.Errorf("cannot parse bigInt from safe nonce %s", safe.Nonce)
+	return g.NewSafeTx(g.BridgeAddress, data, nonce), nil
+
+}
+
+// NewTransferTx creates safe tx at nonce, transferring (unlocking) ERC-20 tokens held by the safe
+func (g *Gnosis) NewTransferTx(tokenAddress string, recipientAddress string, amount *big.Int, nonce int64) (*SafeTx, error) {
+
+	data, err := abiutil.GenerateTransferTxData(recipientAddress, amount)
+	if err != nil {
+		return nil, err
 	}
 
-	data, err := abiutil.GenerateMintTxData(tokenAddress, recipientAddress, amount)
+	return g.NewSafeTx(tokenAddress, data, nonce), nil
+
+}
+
+// NewRefundTx creates safe tx at nonce, returning ERC-20 tokens of the lock transfer at txid and log index to the sender
+// Reference of the lock transfer is appended to the transfer data, so that executed refunds can be found on chain
+func (g *Gnosis) NewRefundTx(tokenAddress string, senderAddress string, amount *big.Int, txid common.Hash, logIndex uint, nonce int64) (*SafeTx, error) {
+
+	data, err := abiutil.GenerateRefundTxData(senderAddress, amount, txid, logIndex)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	// get contract transaction hash
+	return g.NewSafeTx(tokenAddress, data, nonce), nil
+
+}
+
+// NewCancelTx creates empty call of the safe itself at nonce. Once executed, it consumes the nonce,
+// so any other pending tx with the same nonce can not be executed anymore
+func (g *Gnosis) NewCancelTx(nonce int64) *SafeTx {
+	return g.NewSafeTx(g.SafeAddress, []byte{}, nonce)
+}
+
+// SafeTxFromMultisigTx converts tx from the safe api to safe tx of this safe
+func (g *Gnosis) SafeTxFromMultisigTx(tx *MultisigTx) (*SafeTx, error) {
+
+	if !common.IsHexAddress(tx.To) {
+		return nil, fmt.Errorf("invalid safe tx target %s", tx.To)
+	}
+
+	// delegatecall runs target code in the context of the safe, such txs are not signed or relayed by the bridge
+	if tx.Operation != OPERATION_CALL {
+		return nil, fmt.Errorf("invalid safe tx operation %d, only calls are supported", tx.Operation)
+	}
+
+	if tx.Value < 0 || tx.SafeTxGas < 0 || tx.BaseGas < 0 || tx.GasPrice < 0 {
+		return nil, fmt.Errorf("negative value or gas params of safe tx %s", tx.SafeTxHash)
+	}
+
+	var data []byte
+	if tx.Data != "" {
+		var err error
+		data, err = hexutil.Decode(tx.Data)
+		if err != nil {
+			return nil, fmt.Errorf("can not decode safe tx data: %s", err)
+		}
+	}
+
+	safeTx := g.NewSafeTx(tx.To, data, tx.Nonce)
+	safeTx.Value = big.NewInt(tx.Value)
+	safeTx.Operation = uint8(tx.Operation)
+	safeTx.SafeTxGas = big.NewInt(tx.SafeTxGas)
+	safeTx.BaseGas = big.NewInt(tx.BaseGas)
+	safeTx.GasPrice = big.NewInt(tx.GasPrice)
+	if tx.GasToken != "" {
+		safeTx.GasToken = common.HexToAddress(tx.GasToken)
+	}
+	if tx.RefundReceiver != "" {
+		safeTx.RefundReceiver = common.HexToAddress(tx.RefundReceiver)
+	}
+
+	return safeTx, nil
+
+}
+
+// HashData encodes EIP-712 data of safe tx, the same as safe encodeTransactionData
+func (tx *SafeTx) HashData() ([]byte, error) {
+
+	data := tx.Data
+
 	gnosisSafeTx := core.GnosisSafeTx{
-		Safe:           common.NewMixedcaseAddress(common.HexToAddress(g.SafeAddress)),
-		To:             common.NewMixedcaseAddress(common.HexToAddress(g.BridgeAddress)),
-		Value:          *math.NewDecimal256(0),
-		GasPrice:       *math.NewDecimal256(0),
+		Safe:           common.NewMixedcaseAddress(tx.Safe),
+		To:             common.NewMixedcaseAddress(tx.To),
+		Value:          math.Decimal256(*abiutil.BigOrZero(tx.Value)),
+		GasPrice:       math.Decimal256(*abiutil.BigOrZero(tx.GasPrice)),
 		Data:           (*hexutil.Bytes)(&data),
-		Operation:      0,
-		GasToken:       common.HexToAddress(abiutil.ZERO_ADDR),
-		RefundReceiver: common.HexToAddress(abiutil.ZERO_ADDR),
-		BaseGas:        *big.NewInt(0),
-		SafeTxGas:      *big.NewInt(0),
-		Nonce:          *nonce,
-		ChainId:        math.NewHexOrDecimal256(int64(g.ChainId)),
+		Operation:      tx.Operation,
+		GasToken:       tx.GasToken,
+		RefundReceiver: tx.RefundReceiver,
+		BaseGas:        *abiutil.BigOrZero(tx.BaseGas),
+		SafeTxGas:      *abiutil.BigOrZero(tx.SafeTxGas),
+		Nonce:          *big.NewInt(tx.Nonce),
+		ChainId:        math.NewHexOrDecimal256(int64(tx.ChainId)),
 	}
 
 	typedData := gnosisSafeTx.ToTypedData()
 
 	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	typedDataHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	rawData := []byte(fmt.Sprintf("\x19\x01%s%s", string(domainSeparator), string(typedDataHash)))
-	sighash := crypto.Keccak256Hash(rawData)
 
-	contractTxHash, err := hexutil.Decode(sighash.Hex())
+	return rawData, nil
+
+}
+
+// Hash calculates EIP-712 hash of safe tx (safeTxHash)
+func (tx *SafeTx) Hash() ([]byte, error) {
+
+	rawData, err := tx.HashData()
+	if err != nil {
+		return nil, err
+	}
+
+	return crypto.Keccak256(rawData), nil
+
+}
+
+// Sign signs safe tx hash with the key, returns the hash and EIP-712 signature with v = 27 or 28
+func (tx *SafeTx) Sign(key *ecdsa.PrivateKey) ([]byte, []byte, error) {
+
+	hash, err := tx.Hash()
 	if err != nil {
 		return nil, nil, err
 	}
 
-	signature, err := crypto.Sign(contractTxHash, g.PrivateKey)
+	signature, err := crypto.Sign(hash, key)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -73,6 +204,79 @@ func (g *Gnosis) SignMintTx(tokenAddress string, recipientAddress string, amount
 		signature[64] += 27
 	}
 
-	return contractTxHash, signature, nil
+	return hash, signature, nil
+
+}
+
+// EncodeExec generates execTransaction input data of safe tx with encoded owner signatures
+func (tx *SafeTx) EncodeExec(signatures []byte) ([]byte, error) {
+
+	params := &abiutil.ExecTransactionParams{
+		Value:          tx.Value,
+		Operation:      tx.Operation,
+		SafeTxGas:      tx.SafeTxGas,
+		BaseGas:        tx.BaseGas,
+		GasPrice:       tx.GasPrice,
+		GasToken:       tx.GasToken,
+		RefundReceiver: tx.RefundReceiver,
+	}
+
+	return abiutil.GenerateExecTransaction(tx.To.Hex(), hexutil.Encode(tx.Data), hexutil.Encode(signatures), params)
+
+}
+
+// SignSafeTx signs safe tx with the bridge key and returns it in the safe api format
+func (g *Gnosis) SignSafeTx(tx *SafeTx) (*NewMultisigTx, error) {
+
+	for _, value := range []*big.Int{tx.Value, tx.SafeTxGas, tx.BaseGas, tx.GasPrice} {
+		if value != nil && !value.IsInt64() {
+			return nil, fmt.Errorf("safe tx value or gas params are out of range")
+		}
+	}
+
+	hash, signature, err := tx.Sign(g.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	safeTx := &NewMultisigTx{}
+	safeTx.Safe = tx.Safe.Hex()
+	safeTx.To = tx.To.Hex()
+	safeTx.Value = abiutil.BigOrZero(tx.Value).Int64()
+	safeTx.Data = hexutil.Encode(tx.Data)
+	safeTx.Operation = int64(tx.Operation)
+	safeTx.GasToken = tx.GasToken.Hex()
+	safeTx.SafeTxGas = abiutil.BigOrZero(tx.SafeTxGas).Int64()
+	safeTx.BaseGas = abiutil.BigOrZero(tx.BaseGas).Int64()
+	safeTx.GasPrice = abiutil.BigOrZero(tx.GasPrice).Int64()
+	safeTx.RefundReceiver = tx.RefundReceiver.Hex()
+	safeTx.Nonce = tx.Nonce
+	safeTx.ContractTransactionHash = hexutil.Encode(hash)
+	safeTx.Sender = g.PublicKey.Hex()
+	safeTx.Signature = hexutil.Encode(signature)
+
+	return safeTx, nil
+
+}
+
+// ProposeSafeTx signs safe tx and submits it with the signature to the safe api
+// if safeTxHash is not empty, hash of the tx should match it
+func (g *Gnosis) ProposeSafeTx(tx *SafeTx, safeTxHash string) (*NewMultisigTx, error) {
+
+	safeTx, err := g.SignSafeTx(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if safeTxHash != "" && !strings.EqualFold(safeTx.ContractTransactionHash, safeTxHash) {
+		return nil, fmt.Errorf("generated safe tx hash %s does not match %s", safeTx.ContractTransactionHash, safeTxHash)
+	}
+
+	err = g.CreateSafeMultisigTx(safeTx)
+	if err != nil {
+		return nil, err
+	}
+
+	return safeTx, nil
 
 }
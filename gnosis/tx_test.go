@@ -1 +1,137 @@
 package gnosis
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/AccumulateNetwork/bridge/abiutil"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSafeTxHash(t *testing.T) {
+
+	g := &Gnosis{ChainId: 5, SafeAddress: "0x24BbA5D6fD7fC2Cbc293FDa6721c9BE6756D177a", BridgeAddress: "0x903f0dA0697FC1c81ecACc83b2A7445F392399e8"}
+	_, err := g.ImportPrivateKey("08108aadbbe82e9ffa1eba54158e7aacbec6115156242558ae7e594037220e4a")
+	assert.NoError(t, err)
+
+	tx, err := g.NewMintTx("0x4E780D102AADECF1BdC06d91542cf91960538a2D", "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314", big.NewInt(1e8), 7)
+	assert.NoError(t, err)
+
+	// TEST 1: mint tx hash
+	hash, err := tx.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, "0xc52127145fce6750fb49b5d48f7b7fc66524e7e977681f6875787b596f1ad619", hexutil.Encode(hash))
+
+	// TEST 2: signed tx in the safe api format
+	signed, err := g.SignSafeTx(tx)
+	assert.NoError(t, err)
+	assert.Equal(t, hexutil.Encode(hash), signed.ContractTransactionHash)
+	assert.Equal(t, int64(7), signed.Nonce)
+	assert.Equal(t, g.PublicKey.Hex(), signed.Sender)
+	_, signer, err := g.RecoverSigner(g.BridgeAddress, tx.Data, 7, hexutil.MustDecode(signed.Signature))
+	assert.NoError(t, err)
+	assert.Equal(t, g.PublicKey, signer)
+
+	// TEST 3: every param is a part of the hash
+	tx.Value = big.NewInt(1)
+	valueHash, err := tx.Hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, valueHash)
+	tx.Value = big.NewInt(0)
+	tx.RefundReceiver = common.HexToAddress("0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314")
+	refundHash, err := tx.Hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, refundHash)
+
+	// TEST 4: tx from the safe api has the same hash
+	apiTx := &MultisigTx{To: g.BridgeAddress, Data: hexutil.Encode(tx.Data), Nonce: 7, RefundReceiver: tx.RefundReceiver.Hex(), GasToken: abiutil.ZERO_ADDR}
+	converted, err := g.SafeTxFromMultisigTx(apiTx)
+	assert.NoError(t, err)
+	convertedHash, err := converted.Hash()
+	assert.NoError(t, err)
+	assert.Equal(t, refundHash, convertedHash)
+
+	// TEST 5: invalid operation and delegatecall
+	apiTx.Operation = 2
+	_, err = g.SafeTxFromMultisigTx(apiTx)
+	assert.Error(t, err)
+
+	apiTx.Operation = OPERATION_DELEGATECALL
+	_, err = g.SafeTxFromMultisigTx(apiTx)
+	assert.Error(t, err)
+
+	// TEST 6: cancellation is empty call of the safe
+	cancel := g.NewCancelTx(7)
+	assert.Equal(t, common.HexToAddress(g.SafeAddress), cancel.To)
+	assert.Empty(t, cancel.Data)
+	cancelHash, err := cancel.Hash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, cancelHash)
+
+}
+
+func TestSafeTxEncodeExec(t *testing.T) {
+
+	g := &Gnosis{ChainId: 5, SafeAddress: "0x24BbA5D6fD7fC2Cbc293FDa6721c9BE6756D177a", BridgeAddress: "0x903f0dA0697FC1c81ecACc83b2A7445F392399e8"}
+
+	tx := g.NewSafeTx(g.BridgeAddress, []byte{1, 2, 3}, 7)
+	tx.Value = big.NewInt(5)
+	tx.GasPrice = big.NewInt(10)
+	tx.RefundReceiver = common.HexToAddress("0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314")
+
+	signatures := []byte{4, 5, 6}
+
+	input, err := tx.EncodeExec(signatures)
+	assert.NoError(t, err)
+
+	safeABI, err := abiutil.NewABI([]byte(abiutil.GNOSIS_ABI))
+	assert.NoError(t, err)
+
+	method := safeABI.Methods["execTransaction"]
+	assert.Equal(t, method.ID, input[:4])
+
+	args, err := method.Inputs.Unpack(input[4:])
+	assert.NoError(t, err)
+
+	// TEST 1: all params are encoded
+	assert.Equal(t, tx.To, args[0])
+	assert.Equal(t, big.NewInt(5), args[1])
+	assert.Equal(t, tx.Data, args[2])
+	assert.Equal(t, uint8(0), args[3])
+	assert.Equal(t, big.NewInt(10), args[6])
+	assert.Equal(t, common.Address{}, args[7])
+	assert.Equal(t, tx.RefundReceiver, args[8])
+	assert.Equal(t, signatures, args[9])
+
+}
+
+func TestRefundSafeTx(t *testing.T) {
+
+	g := &Gnosis{ChainId: 5, SafeAddress: "0x24BbA5D6fD7fC2Cbc293FDa6721c9BE6756D177a", BridgeAddress: "0x903f0dA0697FC1c81ecACc83b2A7445F392399e8"}
+
+	token := "0x4E780D102AADECF1BdC06d91542cf91960538a2D"
+	sender := "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"
+	txid := common.HexToHash("0x6d1b3b1d8e7d4f4b8a3e6c9f0a2b5d7e9c1f3a5b7d9e1f3a5c7e9b1d3f5a7c9e")
+
+	tx, err := g.NewRefundTx(token, sender, big.NewInt(1e8), txid, 2, 7)
+	assert.NoError(t, err)
+
+	// TEST 1: refund is a call of the token
+	assert.Equal(t, common.HexToAddress(token), tx.To)
+	assert.Equal(t, int64(7), tx.Nonce)
+
+	// TEST 2: transfer data with the lock reference
+	data, err := abiutil.GenerateRefundTxData(sender, big.NewInt(1e8), txid, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, data, tx.Data)
+
+	// TEST 3: reference is found in execTransaction input, that is how executed refunds are found on chain
+	input, err := tx.EncodeExec([]byte{1, 2, 3})
+	assert.NoError(t, err)
+	assert.True(t, bytes.Contains(input, abiutil.RefundReference(txid, 2)))
+	assert.False(t, bytes.Contains(input, abiutil.RefundReference(txid, 1)))
+
+}
@@ -0,0 +1,130 @@
+package leader
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HEARTBEAT_MAX_SKEW is how far in the future heartbeat timestamps are accepted
+const HEARTBEAT_MAX_SKEW = time.Minute
+
+// Heartbeats is the latest heartbeat time of each node by public key hash
+type Heartbeats map[string]time.Time
+
+// Add records heartbeat of the node, keeps the latest one. Heartbeats from the future are ignored
+func (h Heartbeats) Add(key string, timestamp time.Time, now time.Time) {
+
+	if timestamp.After(now.Add(HEARTBEAT_MAX_SKEW)) {
+		return
+	}
+
+	key = strings.ToLower(key)
+
+	if timestamp.After(h[key]) {
+		h[key] = timestamp
+	}
+
+}
+
+// IsAlive checks if the node has sent a heartbeat within the timeout
+func (h Heartbeats) IsAlive(key string, now time.Time, timeout time.Duration) bool {
+
+	last, ok := h[strings.ToLower(key)]
+	if !ok {
+		return false
+	}
+
+	return now.Sub(last) <= timeout
+
+}
+
+// Elect returns the node that acts as leader: the designated leader while it is alive, otherwise the first alive key
+// following it in the key page order. Every node gets the same result from the same key page and heartbeats.
+// If no key is alive, the designated leader is returned
+func Elect(keys []string, designated string, heartbeats Heartbeats, now time.Time, timeout time.Duration) string {
+
+	designated = strings.ToLower(designated)
+
+	if heartbeats.IsAlive(designated, now, timeout) {
+		return designated
+	}
+
+	// rotation starts after the designated leader, or from the first key if it is not on the key page
+	start := 0
+	for i, key := range keys {
+		if strings.EqualFold(key, designated) {
+			start = i + 1
+			break
+		}
+	}
+
+	for i := 0; i < len(keys); i++ {
+		key := strings.ToLower(keys[(start+i)%len(keys)])
+		if heartbeats.IsAlive(key, now, timeout) {
+			return key
+		}
+	}
+
+	return designated
+
+}
+
+// Lease is leadership of a node for a term
+type Lease struct {
+	Leader  string    // public key hash of the leader
+	Term    int64     // number of leader data account entries, increases with every leader change
+	Expires time.Time // the leader stops acting as leader at expiry, if the lease is not renewed
+}
+
+// IsHeldBy checks if the lease belongs to the node and is not expired
+func (l *Lease) IsHeldBy(key string, now time.Time) bool {
+	return l != nil && l.Leader != "" && strings.EqualFold(l.Leader, key) && now.Before(l.Expires)
+}
+
+// Takeover is leader data account entry, written by the next leader when the leader is silent. It is signed with the bridge
+// key page, so it is applied only once enough nodes agree that the leader is silent and co-sign it
+type Takeover struct {
+	Leader string // public key hash of the next leader
+	Term   int64  // term ended by the takeover, takeovers pending since an earlier term are not co-signed
+}
+
+// Content returns data fields of the takeover entry
+func (t *Takeover) Content() ([][]byte, error) {
+
+	leaderBytes, err := hex.DecodeString(t.Leader)
+	if err != nil {
+		return nil, fmt.Errorf("invalid leader %s", t.Leader)
+	}
+
+	return [][]byte{leaderBytes, []byte(strconv.FormatInt(t.Term, 10))}, nil
+
+}
+
+// ParseTakeover parses hex encoded data fields of leader data account entry.
+// Entries written by set-leader have no term and are not takeovers
+func ParseTakeover(data []string) (*Takeover, error) {
+
+	if len(data) != 2 {
+		return nil, fmt.Errorf("looking for 2 data fields in takeover entry, found %d", len(data))
+	}
+
+	if _, err := hex.DecodeString(data[0]); err != nil {
+		return nil, fmt.Errorf("invalid leader %s", data[0])
+	}
+
+	termBytes, err := hex.DecodeString(data[1])
+	if err != nil {
+		return nil, fmt.Errorf("can not decode entry data")
+	}
+
+	term, err := strconv.ParseInt(string(termBytes), 10, 64)
+	if err != nil || term <= 0 {
+		return nil, fmt.Errorf("invalid takeover term %s", termBytes)
+	}
+
+	return &Takeover{Leader: strings.ToLower(data[0]), Term: term}, nil
+
+}
@@ -0,0 +1,105 @@
+package leader
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElect(t *testing.T) {
+
+	now := time.Unix(1700000000, 0)
+	timeout := 10 * time.Minute
+
+	keys := []string{"aa", "bb", "cc", "dd"}
+
+	heartbeats := Heartbeats{}
+	heartbeats.Add("AA", now.Add(-time.Minute), now)
+	heartbeats.Add("bb", now.Add(-20*time.Minute), now)
+	heartbeats.Add("cc", now.Add(-2*time.Minute), now)
+	heartbeats.Add("dd", now.Add(-time.Minute), now)
+
+	// TEST 1: designated leader is alive
+	assert.Equal(t, "aa", Elect(keys, "AA", heartbeats, now, timeout))
+
+	// TEST 2: designated leader is silent, the next alive key takes over
+	assert.Equal(t, "cc", Elect(keys, "bb", heartbeats, now, timeout))
+
+	// TEST 3: rotation wraps around the key page
+	heartbeats["aa"] = now.Add(-20 * time.Minute)
+	heartbeats["cc"] = now.Add(-20 * time.Minute)
+	assert.Equal(t, "dd", Elect(keys, "cc", heartbeats, now, timeout))
+	heartbeats["dd"] = now.Add(-20 * time.Minute)
+	heartbeats["bb"] = now
+	assert.Equal(t, "bb", Elect(keys, "cc", heartbeats, now, timeout))
+
+	// TEST 4: designated leader is not on the key page
+	assert.Equal(t, "bb", Elect(keys, "ee", heartbeats, now, timeout))
+
+	// TEST 5: no key is alive
+	assert.Equal(t, "cc", Elect(keys, "cc", Heartbeats{}, now, timeout))
+
+	// TEST 6: the latest heartbeat is kept, heartbeats from the future are ignored
+	heartbeats = Heartbeats{}
+	heartbeats.Add("aa", now.Add(-time.Minute), now)
+	heartbeats.Add("aa", now.Add(-5*time.Minute), now)
+	heartbeats.Add("bb", now.Add(time.Hour), now)
+	assert.Equal(t, now.Add(-time.Minute), heartbeats["aa"])
+	assert.False(t, heartbeats.IsAlive("bb", now, timeout))
+
+}
+
+func TestLease(t *testing.T) {
+
+	now := time.Unix(1700000000, 0)
+
+	lease := &Lease{Leader: "aa", Term: 3, Expires: now.Add(time.Minute)}
+
+	// TEST 1: lease is held by the leader until expiry
+	assert.True(t, lease.IsHeldBy("AA", now))
+	assert.False(t, lease.IsHeldBy("bb", now))
+	assert.False(t, lease.IsHeldBy("aa", now.Add(time.Minute)))
+
+	// TEST 2: no leader until the takeover is written
+	lease.Expires = time.Time{}
+	assert.False(t, lease.IsHeldBy("aa", now))
+	assert.False(t, (&Lease{Expires: now.Add(time.Minute)}).IsHeldBy("", now))
+	assert.False(t, (*Lease)(nil).IsHeldBy("aa", now))
+
+}
+
+func TestTakeover(t *testing.T) {
+
+	encode := func(content [][]byte) []string {
+		var data []string
+		for _, field := range content {
+			data = append(data, hex.EncodeToString(field))
+		}
+		return data
+	}
+
+	// TEST 1: takeover content is parsed back
+	content, err := (&Takeover{Leader: "AABB", Term: 3}).Content()
+	assert.NoError(t, err)
+
+	takeover, err := ParseTakeover(encode(content))
+	assert.NoError(t, err)
+	assert.Equal(t, &Takeover{Leader: "aabb", Term: 3}, takeover)
+
+	// TEST 2: set-leader entry is not a takeover
+	_, err = ParseTakeover(encode([][]byte{{0xaa, 0xbb}}))
+	assert.Error(t, err)
+
+	// TEST 3: invalid term
+	_, err = ParseTakeover(encode([][]byte{{0xaa, 0xbb}, []byte("0")}))
+	assert.Error(t, err)
+	_, err = ParseTakeover(encode([][]byte{{0xaa, 0xbb}, []byte("x")}))
+	assert.Error(t, err)
+
+	// TEST 4: invalid leader
+	_, err = (&Takeover{Leader: "xx", Term: 3}).Content()
+	assert.Error(t, err)
+
+}
@@ -4,41 +4,57 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"math/big"
+	"os"
 	"os/user"
 	"path/filepath"
-	"sort"
-	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/AccumulateNetwork/bridge/abiutil"
 	"github.com/AccumulateNetwork/bridge/accumulate"
 	"github.com/AccumulateNetwork/bridge/api"
+	"github.com/AccumulateNetwork/bridge/audit"
 	"github.com/AccumulateNetwork/bridge/config"
+	"github.com/AccumulateNetwork/bridge/credits"
 	"github.com/AccumulateNetwork/bridge/evm"
 	"github.com/AccumulateNetwork/bridge/fees"
 	"github.com/AccumulateNetwork/bridge/global"
 	"github.com/AccumulateNetwork/bridge/gnosis"
+	"github.com/AccumulateNetwork/bridge/leader"
 	"github.com/AccumulateNetwork/bridge/schema"
 	acmeurl "github.com/AccumulateNetwork/bridge/url"
 	"github.com/AccumulateNetwork/bridge/utils"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/gommon/log"
 )
 
 const LEADER_MIN_DURATION = 2
+const LEADER_LEASE_DURATION = 2 * time.Minute
+const MAX_HEARTBEAT_ENTRIES = 1000
 const NUMBER_OF_ACCUMULATE_TOKEN_TXS = 100
 const NUMBER_OF_TOKEN_REGISTRY_ENTRIES = 1000
+const NUMBER_OF_REJECTION_ENTRIES = 100
+const CREDITS_TOP_UP_COOLDOWN = 10 // minutes between automatic key page refills
+
+// errTokenQuery is returned by parseToken if token can not be verified because of api errors
+var errTokenQuery = errors.New("token query failed")
 
 var LatestCheckedDeposits map[string]int64
-var LatestCheckedEVMHeight int64
+var LatestCheckedEVMLog *schema.BurnEvent // position of the latest processed burn log or scanned block
+var ScanProgressFile string               // file, where LatestCheckedEVMLog is kept between restarts
+
+// rejections data account is append only, so rejections are read once and only new entries are read afterwards
+var readRejections = make(map[string]audit.RejectionCache) // verified rejections of unsettled entries by rejections data account
+var readRejectionsTotal = make(map[string]int64)           // number of read entries by rejections data account
+var readRejectionsLock sync.Mutex                          // guards readRejections, which are read by mint and release loops
 
 func main() {
 
@@ -112,6 +128,13 @@ func start(configFile string) {
 		// set chainId for tokens
 		global.Tokens.ChainID = int64(conf.EVM.ChainId)
 
+		// restore burn logs scan progress
+		// scan progress is saved next to the config file
+		ScanProgressFile = filepath.Join(filepath.Dir(configFile), fmt.Sprintf("scan-%d.json", conf.EVM.ChainId))
+		if err = loadScanProgress(); err != nil {
+			log.Fatal(err)
+		}
+
 		// parse bridge fees on node start
 		bridgeFeesDataAccount := filepath.Join(conf.ACME.BridgeADI, accumulate.ACC_BRIDGE_FEES)
 		if err = getBridgeFees(bridgeFeesDataAccount, a); err != nil {
@@ -123,24 +146,23 @@ func start(configFile string) {
 		fmt.Printf("Burn fee: %.2f%%\n", float64(global.BridgeFees.BurnFee)/100)
 
 		// parse token list from Accumulate
-		// only once – when node is started
 		// token list is mandatory, so return fatal error in case of error
+		// the list is refreshed by the token registry watcher afterwards
 		tokensDataAccount := filepath.Join(conf.ACME.BridgeADI, accumulate.ACC_TOKEN_REGISTRY)
 		fmt.Println("Getting Accumulate tokens from", tokensDataAccount)
-		tokens, err := a.QueryDataSet(&accumulate.Params{URL: tokensDataAccount, Count: int64(NUMBER_OF_TOKEN_REGISTRY_ENTRIES), Expand: true})
+		tokens, entryHashes, err := loadTokens(tokensDataAccount, a, e, g, false)
 		if err != nil {
 			fmt.Println("unable to get token list from", tokensDataAccount)
 			log.Fatal(err)
 		}
 
-		fmt.Println("Got", len(tokens.Items), "data entry(s)")
-		for _, item := range tokens.Items {
-			parseToken(a, e, g, item)
+		for _, change := range utils.ReplaceTokens(tokens) {
+			log.Info("token ", change.URL, " ", change.Action)
 		}
 
-		fmt.Println("Found", len(global.Tokens.Items), "token(s)")
+		fmt.Println("Found", len(tokens), "token(s)")
 
-		if len(global.Tokens.Items) == 0 {
+		if len(tokens) == 0 {
 			log.Fatal("can not operate without tokens, shutting down")
 		}
 
@@ -150,13 +172,29 @@ func start(configFile string) {
 		// refresh bridge fees every minute
 		go refreshBridgeFees(bridgeFeesDataAccount, a, die)
 
+		// watch token registry for new entries every minute
+		go watchTokenRegistry(tokensDataAccount, entryHashes, a, e, g, die)
+
 		go getStatus(a, die)
-		go getLeader(a, die)
+		go getLeader(a, conf, die)
+		go monitorCredits(a, conf, die)
 		// go debugLeader(die)
 
-		go processBurnEvents(a, e, conf.EVM.BridgeAddress, die)
+		go processBurnEvents(a, e, conf.EVM.BridgeAddress, g.SafeAddress, die)
 		go processNewDeposits(a, e, g, die)
-		go submitEVMTxs(e, g, die)
+
+		// auditors report deposits and burns the leader does not queue
+		if conf.ACME.CensorshipWindow > 0 {
+			go watchCensorship(a, e, conf.EVM.BridgeAddress, g.SafeAddress, time.Duration(conf.ACME.CensorshipWindow)*time.Minute, die)
+		}
+
+		// track submitted EVM txs until they are mined
+		// tracked txs are saved next to the config file
+		txm, err := evm.NewTxManager(e, time.Duration(conf.EVM.StuckTxTimeout)*time.Minute, filepath.Join(filepath.Dir(configFile), "txs.json"))
+		if err != nil {
+			log.Fatal(err)
+		}
+		go submitEVMTxs(a, e, g, txm, die)
 
 		// init Accumulate Bridge API
 		fmt.Println("Starting Accumulate Bridge API at port", conf.App.APIPort)
@@ -210,47 +248,63 @@ func refreshBridgeFees(bridgeFeesDataAccount string, a *accumulate.AccumulateCli
 	}
 }
 
-// getLeader parses current leader's public key hash from Accumulate data account and compares it with Accumulate key in the config to find out if this node is a leader or not
-func getLeader(a *accumulate.AccumulateClient, die chan bool) {
+// getLeader reads the leader lease from Accumulate data account and compares it with Accumulate key in the config to find out if this node is a leader or not
+func getLeader(a *accumulate.AccumulateClient, conf *config.Config, die chan bool) {
 
 	leaderDataAccount := filepath.Join(a.ADI, accumulate.ACC_LEADER)
+	heartbeatDataAccount := filepath.Join(a.ADI, accumulate.ACC_HEARTBEAT)
+
+	// failover is enabled if leader timeout is set
+	timeout := time.Duration(conf.ACME.LeaderTimeout) * time.Minute
+	started := time.Now()
+
+	self := hex.EncodeToString(a.PublicKeyHash)
+
+	// term, at which this node took over leadership, to write takeover only once
+	var takeoverTerm int64
+
+	// pending takeovers signed by this node
+	signedTakeovers := make(map[string]bool)
 
 	for {
 
 		select {
 		default:
 
-			leaderData, err := a.QueryLatestDataEntry(&accumulate.Params{URL: leaderDataAccount})
+			if timeout > 0 {
+				publishHeartbeat(a, heartbeatDataAccount)
+			}
+
+			lease, err := getLeaderLease(a, leaderDataAccount, heartbeatDataAccount, timeout, started, &takeoverTerm, signedTakeovers)
 			if err != nil {
 				fmt.Println("[leader] Unable to read bridge leader:", err)
 				global.IsLeader = false
 				global.IsAudit = false
 				global.LeaderDuration = 0
-			} else {
-				fmt.Println("[leader] Bridge leader:", leaderData.Data.Entry.Data[0])
-				decodedLeader, err := hex.DecodeString(leaderData.Data.Entry.Data[0])
-				if err != nil {
-					fmt.Println(err)
+			} else if lease.IsHeldBy(self, time.Now()) {
+				// the lease is renewed on every check, term changes when leader changes
+				if lease.Term != global.LeaderTerm {
 					global.IsLeader = false
-					global.IsAudit = false
 					global.LeaderDuration = 0
 				}
-				if bytes.Equal(decodedLeader, a.PublicKeyHash) {
-					global.IsAudit = false
-					global.LeaderDuration++
-					if !global.IsLeader {
-						if global.LeaderDuration <= LEADER_MIN_DURATION {
-							fmt.Println("[leader] This node is leader, confirmations:", global.LeaderDuration, "of", LEADER_MIN_DURATION)
-						}
-						if global.LeaderDuration >= LEADER_MIN_DURATION {
-							global.IsLeader = true
-						}
+				global.IsAudit = false
+				global.LeaderTerm = lease.Term
+				global.LeaseExpires = lease.Expires
+				global.LeaderDuration++
+				if !global.IsLeader {
+					if global.LeaderDuration <= LEADER_MIN_DURATION {
+						fmt.Println("[leader] This node is leader, term:", lease.Term, "confirmations:", global.LeaderDuration, "of", LEADER_MIN_DURATION)
+					}
+					if global.LeaderDuration >= LEADER_MIN_DURATION {
+						global.IsLeader = true
 					}
-				} else {
-					global.IsLeader = false
-					global.IsAudit = true
-					global.LeaderDuration = 0
 				}
+			} else {
+				global.IsLeader = false
+				global.IsAudit = true
+				global.LeaderDuration = 0
+				global.LeaderTerm = lease.Term
+				global.LeaseExpires = time.Time{}
 			}
 
 			// check leader every minute
@@ -264,6 +318,311 @@ func getLeader(a *accumulate.AccumulateClient, die chan bool) {
 
 }
 
+// getLeaderLease returns the lease of the leader from the leader data account. Its term is the number of entries in the account.
+// If failover is enabled and the leader is silent past the timeout, the next alive key of the key page takes over by writing
+// itself to the leader data account with the bridge key page, the takeover starts a new term once other nodes co-sign it
+func getLeaderLease(a *accumulate.AccumulateClient, leaderDataAccount string, heartbeatDataAccount string, timeout time.Duration, started time.Time, takeoverTerm *int64, signedTakeovers map[string]bool) (*leader.Lease, error) {
+
+	leaderData, err := a.QueryLatestDataEntry(&accumulate.Params{URL: leaderDataAccount})
+	if err != nil {
+		return nil, err
+	}
+
+	// get total number of entries, that is the term of the latest entry
+	dataSet, err := a.QueryDataSet(&accumulate.Params{URL: leaderDataAccount, Count: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	latest, err := a.QueryDataSet(&accumulate.Params{URL: leaderDataAccount, Start: dataSet.Total - 1, Count: 1, Expand: true})
+	if err != nil {
+		return nil, err
+	}
+
+	// leader was changed between the queries
+	if len(latest.Items) != 1 || latest.Items[0].EntryHash != leaderData.Data.EntryHash {
+		return nil, fmt.Errorf("leader data account was updated, retrying")
+	}
+
+	lease := &leader.Lease{Term: dataSet.Total}
+
+	lease.Leader = strings.ToLower(leaderData.Data.Entry.Data[0])
+	if _, err := hex.DecodeString(lease.Leader); err != nil {
+		return nil, fmt.Errorf("invalid leader %s", lease.Leader)
+	}
+
+	fmt.Println("[leader] Bridge leader:", lease.Leader, "term:", lease.Term)
+
+	now := time.Now()
+
+	// the lease is renewed every minute, the leader stops if it can not renew it
+	lease.Expires = now.Add(LEADER_LEASE_DURATION)
+
+	if timeout == 0 {
+		return lease, nil
+	}
+
+	page, err := a.QueryKeyPage(&accumulate.Params{URL: a.Signer})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, key := range page.Data.Keys {
+		keys = append(keys, strings.ToLower(key.PublicKeyHash))
+	}
+
+	heartbeats, err := getHeartbeats(a, heartbeatDataAccount, keys, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// the leader, which heartbeats are not visible to others, stops acting as leader
+	// well before the others replace it
+	if expires := heartbeats[lease.Leader].Add(timeout / 2); expires.Before(lease.Expires) {
+		lease.Expires = expires
+	}
+
+	// this node replaces the leader only after it has observed heartbeats for the whole timeout
+	if heartbeats.IsAlive(lease.Leader, now, timeout) || now.Sub(started) < timeout {
+		return lease, nil
+	}
+
+	next := leader.Elect(keys, lease.Leader, heartbeats, now, timeout)
+
+	fmt.Println("[leader] Bridge leader is silent for", timeout, "next leader:", next)
+
+	self := hex.EncodeToString(a.PublicKeyHash)
+
+	// takeover of the agreed next leader is co-signed, so it is applied once the key page threshold of nodes see the leader silent
+	found := signTakeovers(a, leaderDataAccount, &leader.Takeover{Leader: next, Term: lease.Term}, signedTakeovers)
+
+	if next == self && !found && *takeoverTerm != lease.Term {
+
+		*takeoverTerm = lease.Term
+
+		content, err := (&leader.Takeover{Leader: self, Term: lease.Term}).Content()
+		if err != nil {
+			return nil, err
+		}
+
+		txhash, err := a.WriteData(leaderDataAccount, content)
+		if err != nil {
+			fmt.Println("[leader] Unable to take over leadership:", err)
+		} else {
+			signedTakeovers[txhash] = true
+			fmt.Println("[leader] Taking over leadership, term:", lease.Term+1, "tx:", txhash)
+		}
+
+	}
+
+	// nobody is leader until the new lease is written
+	lease.Expires = time.Time{}
+
+	return lease, nil
+
+}
+
+// signTakeovers co-signs pending takeovers of the leader data account, which end the term and elect the same next leader as this node.
+// Returns true if such takeover is pending
+func signTakeovers(a *accumulate.AccumulateClient, leaderDataAccount string, expected *leader.Takeover, signed map[string]bool) bool {
+
+	pending, err := a.QueryPendingChain(&accumulate.Params{URL: leaderDataAccount})
+	if err != nil {
+		fmt.Println("[leader] Unable to check pending chain of", leaderDataAccount, err)
+		return false
+	}
+
+	found := false
+
+	for _, entryhash := range pending.Items {
+
+		entry, err := a.QueryDataEntry(&accumulate.Params{URL: accumulate.GenerateDataEntry(leaderDataAccount, entryhash)})
+		if err != nil {
+			fmt.Println("[leader] Unable to get pending entry", entryhash, err)
+			continue
+		}
+
+		takeover, err := leader.ParseTakeover(entry.Data.Entry.Data)
+		if err != nil {
+			continue
+		}
+
+		// takeovers of another leader or term are left pending
+		if !strings.EqualFold(takeover.Leader, expected.Leader) || takeover.Term != expected.Term {
+			continue
+		}
+
+		found = true
+
+		if signed[entryhash] {
+			continue
+		}
+		signed[entryhash] = true
+
+		txhash, err := a.RemoteTransaction(leaderDataAccount, entryhash)
+		if err != nil {
+			fmt.Println("[leader] Unable to sign takeover", entryhash, err)
+			continue
+		}
+
+		fmt.Println("[leader] Signed takeover of", takeover.Leader, "term:", takeover.Term+1, "tx:", txhash)
+
+	}
+
+	return found
+
+}
+
+// publishHeartbeat writes signed heartbeat of this node to heartbeat data account
+func publishHeartbeat(a *accumulate.AccumulateClient, heartbeatDataAccount string) {
+
+	heartbeatBytes, err := json.Marshal(schema.NewHeartbeat(a.ADI, a.PrivateKey, time.Now()))
+	if err != nil {
+		fmt.Println("[leader] Unable to generate heartbeat:", err)
+		return
+	}
+
+	var content [][]byte
+	content = append(content, []byte(accumulate.HEARTBEAT_VERSION))
+	content = append(content, heartbeatBytes)
+
+	txhash, err := a.WriteDataAs(heartbeatDataAccount, a.SigsSigner, content)
+	if err != nil {
+		fmt.Println("[leader] Unable to publish heartbeat:", err)
+		return
+	}
+
+	log.Debug("heartbeat published: ", txhash)
+
+}
+
+// getHeartbeats reads the latest heartbeats of key page keys, each key is expected to send one every minute
+func getHeartbeats(a *accumulate.AccumulateClient, heartbeatDataAccount string, keys []string, timeout time.Duration) (leader.Heartbeats, error) {
+
+	count := int64(len(keys)) * int64(timeout/time.Minute+1)
+	if count > MAX_HEARTBEAT_ENTRIES {
+		count = MAX_HEARTBEAT_ENTRIES
+	}
+
+	// get total number of entries to read the latest ones
+	dataSet, err := a.QueryDataSet(&accumulate.Params{URL: heartbeatDataAccount, Count: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	start := dataSet.Total - count
+	if start < 0 {
+		start = 0
+	}
+
+	dataSet, err = a.QueryDataSet(&accumulate.Params{URL: heartbeatDataAccount, Start: start, Count: count, Expand: true})
+	if err != nil {
+		return nil, err
+	}
+
+	isKey := make(map[string]bool)
+	for _, key := range keys {
+		isKey[key] = true
+	}
+
+	now := time.Now()
+	heartbeats := leader.Heartbeats{}
+
+	for _, entry := range dataSet.Items {
+
+		heartbeat, err := schema.ParseHeartbeat(entry)
+		if err != nil {
+			continue
+		}
+
+		// heartbeats are accepted only from keys of the key page
+		key, err := heartbeat.Verify(a.ADI)
+		if err != nil || !isKey[key] {
+			log.Debug("invalid heartbeat ", entry.EntryHash)
+			continue
+		}
+
+		heartbeats.Add(key, time.Unix(heartbeat.Timestamp, 0), now)
+
+	}
+
+	return heartbeats, nil
+
+}
+
+// monitorCredits tracks credit balance of the bridge key page, warns if it is low and optionally refills it
+func monitorCredits(a *accumulate.AccumulateClient, conf *config.Config, die chan bool) {
+
+	policy := &credits.Policy{
+		MinCredits:   conf.ACME.MinCredits,
+		TopUpAccount: conf.ACME.CreditsTopUpAccount,
+		TopUpAmount:  conf.ACME.CreditsTopUpAmount,
+		Cooldown:     time.Duration(CREDITS_TOP_UP_COOLDOWN) * time.Minute,
+	}
+
+	var lastTopUp time.Time
+
+	for {
+
+		select {
+		default:
+
+			// check credits every minute
+			time.Sleep(time.Duration(1) * time.Minute)
+
+			page, err := a.QueryKeyPage(&accumulate.Params{URL: a.Signer})
+			if err != nil {
+				fmt.Println("[credits] Unable to read key page:", err)
+				break
+			}
+
+			global.CreditBalance = float64(page.Data.CreditBalance) / math.Pow10(accumulate.CREDIT_PRECISION)
+
+			decision, err := policy.Decide(global.CreditBalance, global.IsLeader, lastTopUp, time.Now())
+			if err != nil {
+				fmt.Println("[credits] Invalid top up config:", err)
+			}
+
+			if decision == credits.CREDITS_OK {
+				log.Debug("key page credit balance ", global.CreditBalance)
+				break
+			}
+
+			log.Warn("key page ", a.Signer, " has ", global.CreditBalance, " credits, minimum is ", conf.ACME.MinCredits)
+
+			if decision != credits.CREDITS_TOP_UP {
+				break
+			}
+
+			oracle, err := a.QueryOracle()
+			if err != nil {
+				fmt.Println("[credits] Unable to get oracle price:", err)
+				break
+			}
+
+			amount := policy.Amount(accumulate.ACME_PRECISION)
+
+			fmt.Println("[credits] Adding credits to", a.Signer, "from", conf.ACME.CreditsTopUpAccount, "for", conf.ACME.CreditsTopUpAmount, "ACME")
+
+			txhash, err := a.AddCredits(conf.ACME.CreditsTopUpAccount, a.Signer, amount, oracle)
+			if err != nil {
+				fmt.Println("[credits] tx failed:", err)
+				break
+			}
+
+			lastTopUp = time.Now()
+
+			fmt.Println("[credits] tx sent:", txhash)
+
+		case <-die:
+			return
+		}
+
+	}
+
+}
+
 // getStatus checks if the bridge is online
 func getStatus(a *accumulate.AccumulateClient, die chan bool) {
 
@@ -299,8 +658,120 @@ func getStatus(a *accumulate.AccumulateClient, die chan bool) {
 
 }
 
+// loadTokens reads token registry data account and builds the list of verified tokens
+// entries are applied in order: later entries override or disable earlier ones
+// if strict is true, any api error while verifying an entry fails the whole load, so the current list is kept
+func loadTokens(tokensDataAccount string, a *accumulate.AccumulateClient, e *evm.EVMClient, g *gnosis.Gnosis, strict bool) ([]*schema.Token, []string, error) {
+
+	entries, err := a.QueryDataSet(&accumulate.Params{URL: tokensDataAccount, Count: int64(NUMBER_OF_TOKEN_REGISTRY_ENTRIES), Expand: true})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fmt.Println("Got", len(entries.Items), "data entry(s)")
+
+	var tokens []*schema.Token
+	var entryHashes []string
+
+	for _, item := range entries.Items {
+
+		entryHashes = append(entryHashes, item.EntryHash)
+
+		tokenEntry, token, err := parseToken(a, e, g, item)
+		if err != nil {
+			if strict && errors.Is(err, errTokenQuery) {
+				return nil, nil, err
+			}
+			log.Debug(err)
+			continue
+		}
+
+		// find existing token
+		index := -1
+		for i, t := range tokens {
+			if strings.EqualFold(t.URL, tokenEntry.URL) {
+				index = i
+				break
+			}
+		}
+
+		// if entry is disabled, remove existing token / skip
+		if token == nil {
+			if index >= 0 {
+				log.Debug("remove disabled token ", tokenEntry.URL)
+				tokens = append(tokens[:index], tokens[index+1:]...)
+			}
+			continue
+		}
+
+		// if not found, append new token
+		if index < 0 {
+			tokens = append(tokens, token)
+			continue
+		}
+
+		// duplicate token, override
+		log.Debug("duplicate token ", token.URL, ", overwritten")
+		tokens[index] = token
+
+	}
+
+	return tokens, entryHashes, nil
+
+}
+
+// watchTokenRegistry checks token registry every minute and applies new entries without restarting the node
+func watchTokenRegistry(tokensDataAccount string, entryHashes []string, a *accumulate.AccumulateClient, e *evm.EVMClient, g *gnosis.Gnosis, die chan bool) {
+
+	for {
+		select {
+		default:
+
+			// check token registry every minute
+			time.Sleep(time.Duration(1) * time.Minute)
+
+			entries, err := a.QueryDataSet(&accumulate.Params{URL: tokensDataAccount, Count: int64(NUMBER_OF_TOKEN_REGISTRY_ENTRIES), Expand: true})
+			if err != nil {
+				fmt.Println("[tokens] Unable to read token registry:", err)
+				break
+			}
+
+			// reload only if the registry has new entries
+			changed := len(entries.Items) != len(entryHashes)
+			for i := 0; !changed && i < len(entries.Items); i++ {
+				changed = entries.Items[i].EntryHash != entryHashes[i]
+			}
+			if !changed {
+				break
+			}
+
+			fmt.Println("[tokens] Found new entries in", tokensDataAccount, ", reloading tokens")
+
+			tokens, hashes, err := loadTokens(tokensDataAccount, a, e, g, true)
+			if err != nil {
+				fmt.Println("[tokens] Unable to reload tokens, keeping current list:", err)
+				break
+			}
+
+			entryHashes = hashes
+
+			changes := utils.ReplaceTokens(tokens)
+			for _, change := range changes {
+				log.Info("token ", change.URL, " ", change.Action)
+			}
+
+			fmt.Println("[tokens] Applied", len(changes), "change(s), found", len(tokens), "token(s)")
+
+		case <-die:
+			return
+		}
+
+	}
+}
+
 // parseToken parses data entry with token information received from data account
-func parseToken(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gnosis.Gnosis, entry *accumulate.DataEntry) {
+// returns nil token if entry is disabled
+func parseToken(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gnosis.Gnosis, entry *accumulate.DataEntry) (*schema.TokenEntry, *schema.Token, error) {
 
 	fmt.Println("Parsing", entry.EntryHash)
 
@@ -308,65 +779,55 @@ func parseToken(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gnosis.Gnos
 
 	// check version
 	if len(entry.Entry.Data) < 2 {
-		log.Debug("looking for at least 2 data fields in entry, found ", len(entry.Entry.Data))
-		return
+		return nil, nil, fmt.Errorf("looking for at least 2 data fields in entry, found %d", len(entry.Entry.Data))
 	}
 
 	version, err := hex.DecodeString(entry.Entry.Data[0])
 	if err != nil {
-		log.Debug("can not decode entry data")
-		return
+		return nil, nil, fmt.Errorf("can not decode entry data")
 	}
 
 	if !bytes.Equal(version, []byte(accumulate.TOKEN_REGISTRY_VERSION)) {
-		log.Debug("entry version is not ", accumulate.TOKEN_REGISTRY_VERSION)
-		return
+		return nil, nil, fmt.Errorf("entry version is not %s", accumulate.TOKEN_REGISTRY_VERSION)
 	}
 
 	// convert entry data to bytes
 	tokenData, err := hex.DecodeString(entry.Entry.Data[1])
 	if err != nil {
-		log.Debug("can not decode entry data")
-		return
+		return nil, nil, fmt.Errorf("can not decode entry data")
 	}
 
 	// try to unmarshal the entry
 	err = json.Unmarshal(tokenData, tokenEntry)
 	if err != nil {
-		log.Debug("unable to unmarshal entry data")
-		return
+		return nil, nil, fmt.Errorf("unable to unmarshal entry data")
 	}
 
-	// if entry is disabled, remove existing tokens / skip
+	// if entry is disabled, return without token
 	if !tokenEntry.Enabled {
-		for i, t := range global.Tokens.Items {
-			if strings.EqualFold(t.URL, tokenEntry.URL) {
-				log.Info("remove disabled token ", tokenEntry.URL)
-				global.Tokens.Items = append(global.Tokens.Items[:i], global.Tokens.Items[i+1:]...)
-				return
-			}
-		}
-		log.Debug("token is disabled")
-		return
+		return tokenEntry, nil, nil
 	}
 
 	// validate token
 	validate := validator.New()
 	err = validate.Struct(tokenEntry)
 	if err != nil {
-		log.Debug(err)
-		return
+		return nil, nil, err
 	}
 
 	token := &schema.Token{}
 
+	token.Mode = tokenEntry.Mode
+	if token.Mode == "" {
+		token.Mode = schema.TOKEN_MODE_MINT
+	}
+
 	for _, wrappedToken := range tokenEntry.Wrapped {
 		// search for current chainid
 		if wrappedToken.ChainID == global.Tokens.ChainID {
 			err = validate.Struct(wrappedToken)
 			if err != nil {
-				log.Debug(err)
-				return
+				return nil, nil, err
 			}
 			token.EVMAddress = wrappedToken.Address
 			token.EVMMintTxCost = wrappedToken.MintTxCost
@@ -375,54 +836,580 @@ func parseToken(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gnosis.Gnos
 
 	// if no token address found, error
 	if token.EVMAddress == "" {
-		log.Debug("can not find token address for chainid ", global.Tokens.ChainID)
-		return
+		return nil, nil, fmt.Errorf("can not find token address for chainid %d", global.Tokens.ChainID)
 	}
 
 	// parse token info from Accumulate
 	t, err := a.QueryToken(&accumulate.Params{URL: tokenEntry.URL})
 	if err != nil {
-		log.Debug("can not get token from accumulate api ", err)
-		return
+		return nil, nil, fmt.Errorf("%w: can not get token from accumulate api: %s", errTokenQuery, err)
 	}
 
 	token.URL = t.Data.URL
 	token.Symbol = t.Data.Symbol
 	token.Precision = t.Data.Precision
 
-	// check if bridge has token account on this chain for this token
+	// tokens in lock mode are issued by the bridge, so bridge key book should be the token authority
+	if token.IsLocked() {
+		signer, err := acmeurl.Parse(a.Signer)
+		if err != nil {
+			return nil, nil, err
+		}
+		keyBookPath := filepath.Dir(signer.Path)
+		isAuthority := false
+		for _, authority := range t.Data.Authorities {
+			authorityUrl, err := acmeurl.Parse(authority.URL)
+			if err != nil {
+				continue
+			}
+			if strings.EqualFold(authorityUrl.Authority, signer.Authority) && strings.EqualFold(authorityUrl.Path, keyBookPath) {
+				isAuthority = true
+			}
+		}
+		if !isAuthority {
+			return nil, nil, fmt.Errorf("bridge key book %s%s is not an authority of token %s", signer.Authority, keyBookPath, token.URL)
+		}
+	}
+
+	// check if bridge has token account on this chain for this token
 	tokenAccountUrl := accumulate.GenerateTokenAccount(a.ADI, global.Tokens.ChainID, token.Symbol)
 	_, err = a.QueryTokenAccount(&accumulate.Params{URL: tokenAccountUrl})
 	if err != nil {
-		log.Debug("can not get token account ", tokenAccountUrl, " from accumulate api")
-		return
+		return nil, nil, fmt.Errorf("%w: can not get token account %s from accumulate api: %s", errTokenQuery, tokenAccountUrl, err)
 	}
 
 	// parse token info from Ethereum
 	evmT, err := e.GetERC20(token.EVMAddress)
 	if err != nil {
-		log.Debug("can not get token from ethereum api ", err)
-		return
+		return nil, nil, fmt.Errorf("%w: can not get token from ethereum api: %s", errTokenQuery, err)
 	}
-	if evmT.Owner != g.BridgeAddress {
-		log.Debug("token owner is not the bridge, but ", evmT.Owner)
-		return
+	// wrapped tokens are minted by the bridge, EVM-native tokens are locked in the safe and amounts are not converted
+	if !token.IsLocked() && evmT.Owner != g.BridgeAddress {
+		return nil, nil, fmt.Errorf("token owner is not the bridge, but %s", evmT.Owner)
+	}
+	if token.IsLocked() && evmT.Decimals != token.Precision {
+		return nil, nil, fmt.Errorf("token decimals %d do not match accumulate token precision %d", evmT.Decimals, token.Precision)
 	}
 	token.EVMSymbol = evmT.Symbol
 	token.EVMDecimals = evmT.Decimals
 
-	// check for duplicates, if found override
-	exists := utils.SearchAccumulateToken(token.URL)
+	return tokenEntry, token, nil
 
-	// if not found, append new token
-	if exists == nil {
-		log.Info("added token ", token.URL)
-		global.Tokens.Items = append(global.Tokens.Items, token)
-		return
+}
+
+// signDepositTx generates and signs gnosis safe tx for the deposit at the given safe nonce
+// wrapped tokens are minted via bridge, EVM-native tokens in lock mode are transferred from the safe
+func signDepositTx(g *gnosis.Gnosis, token *schema.Token, recipient string, amount *big.Int, nonce int64) (*gnosis.NewMultisigTx, error) {
+
+	var safeTx *gnosis.SafeTx
+	var err error
+
+	if token.IsLocked() {
+		safeTx, err = g.NewTransferTx(token.EVMAddress, recipient, amount, nonce)
+	} else {
+		safeTx, err = g.NewMintTx(token.EVMAddress, recipient, amount, nonce)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return g.SignSafeTx(safeTx)
+
+}
+
+// getRejections reads rejections of pending queue entries from rejections data account.
+// Rejections, which are not signed by the rejecting node, are skipped, rejections of settled entries are dropped
+func getRejections(a *accumulate.AccumulateClient, chainId int64) (audit.Rejections, error) {
+
+	rejectionsAccount := accumulate.GenerateReleaseDataAccount(a.ADI, chainId, accumulate.ACC_REJECTIONS)
+
+	readRejectionsLock.Lock()
+	defer readRejectionsLock.Unlock()
+
+	if readRejections[rejectionsAccount] == nil {
+		readRejections[rejectionsAccount] = audit.RejectionCache{}
+	}
+
+	// get total number of entries to read the new ones
+	dataSet, err := a.QueryDataSet(&accumulate.Params{URL: rejectionsAccount, Count: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	total := dataSet.Total
+
+	// read new entries page by page
+	for readRejectionsTotal[rejectionsAccount] < total {
+
+		dataSet, err = a.QueryDataSet(&accumulate.Params{URL: rejectionsAccount, Start: readRejectionsTotal[rejectionsAccount], Count: NUMBER_OF_REJECTION_ENTRIES, Expand: true})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(dataSet.Items) == 0 {
+			break
+		}
+
+		for _, entry := range dataSet.Items {
+
+			rejection, err := schema.ParseRejection(entry)
+			if err != nil {
+				fmt.Println("[rejection] can not parse rejection entry", entry.EntryHash, err)
+				continue
+			}
+
+			// keys are checked against the key page when rejections are counted
+			err = rejection.Verify(a.ADI)
+			if err != nil {
+				fmt.Println("[rejection] invalid rejection entry", entry.EntryHash, err)
+				continue
+			}
+
+			readRejections[rejectionsAccount].Add(rejection)
+
+		}
+
+		readRejectionsTotal[rejectionsAccount] += int64(len(dataSet.Items))
+
+	}
+
+	err = readRejections[rejectionsAccount].Prune(func(rejections audit.Rejections) (bool, error) {
+		return isSettledEntry(a, rejections)
+	})
+	if err != nil {
+		fmt.Println("[rejection] can not drop rejections of settled entries:", err)
+	}
+
+	return readRejections[rejectionsAccount].All(), nil
+
+}
+
+// isSettledEntry checks that rejected entry is not pending anymore (executed or expired),
+// and burns of the entry, recovered by the leader, are not pending either
+func isSettledEntry(a *accumulate.AccumulateClient, rejections audit.Rejections) (bool, error) {
+
+	pending, err := isPendingTx(a, rejections[0].Queue, rejections[0].EntryHash)
+	if err != nil || pending {
+		return false, err
+	}
+
+	for _, rejection := range rejections {
+
+		if rejection.BurnTxHash == "" {
+			continue
+		}
+
+		txid, err := acmeurl.ParseTxID(rejection.BurnTxHash)
+		if err != nil {
+			continue
+		}
+
+		hash := txid.Hash()
+
+		pending, err := isPendingTx(a, txid.Account().String(), hex.EncodeToString(hash[:]))
+		if err != nil || pending {
+			return false, err
+		}
+
+	}
+
+	return true, nil
+
+}
+
+// getRejectedEntries returns pending entries of the queue, which can not reach the key page threshold because of rejections
+func getRejectedEntries(a *accumulate.AccumulateClient, rejections audit.Rejections, queue string, pending []string) (map[string]bool, error) {
+
+	rejected := make(map[string]bool)
+
+	if len(rejections) == 0 {
+		return rejected, nil
+	}
+
+	page, err := a.QueryKeyPage(&accumulate.Params{URL: a.Signer})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, key := range page.Data.Keys {
+		keys = append(keys, key.PublicKeyHash)
+	}
+
+	threshold := page.Data.AcceptThreshold
+	if threshold == 0 {
+		threshold = page.Data.Threshold
+	}
+
+	for _, entryhash := range pending {
+		if rejections.IsRejected(queue, entryhash, keys, threshold) {
+			rejected[entryhash] = true
+		}
+	}
+
+	return rejected, nil
+
+}
+
+// rejectEntry votes against pending entry of the queue and publishes the reason to rejections data account.
+// Every node rejects the entry once
+func rejectEntry(a *accumulate.AccumulateClient, chainId int64, rejections audit.Rejections, queue string, entryhash string, recovered *schema.DepositEvent, reason error) error {
+
+	self := hex.EncodeToString(a.PublicKeyHash)
+
+	if rejections.HasRejected(self, queue, entryhash) {
+		return nil
+	}
+
+	// pending tx of the entry is rejected first, so nodes that reject the entry never let its tx execute,
+	// once the entry can not reach the threshold, its tx can not reach it either
+	err := rejectEntryTx(a, chainId, queue, entryhash)
+	if err != nil {
+		return err
+	}
+
+	txhash, err := a.RejectTransaction(queue, entryhash)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("[rejection] Rejected entry", entryhash, "of", queue, "tx:", txhash)
+
+	rejection := &schema.Rejection{
+		Queue:     queue,
+		EntryHash: entryhash,
+		Reason:    reason.Error(),
+	}
+
+	// txs of the mint entry, recovered by the leader, are not reused
+	if recovered != nil {
+		rejection.SafeTxHash = recovered.SafeTxHash
+		rejection.BurnTxHash = recovered.BurnTxHash
+	}
+
+	// rejections are published with threshold 1 key page, so they are signed by the node key
+	err = rejection.Sign(a.ADI, a.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	rejectionBytes, err := json.Marshal(rejection)
+	if err != nil {
+		return err
+	}
+
+	var content [][]byte
+	content = append(content, []byte(accumulate.REJECTIONS_VERSION))
+	content = append(content, rejectionBytes)
+
+	rejectionsAccount := accumulate.GenerateReleaseDataAccount(a.ADI, chainId, accumulate.ACC_REJECTIONS)
+
+	txhash, err = a.WriteDataAs(rejectionsAccount, a.SigsSigner, content)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("[rejection] Published rejection of", entryhash, "reason:", reason, "tx:", txhash)
+
+	return nil
+
+}
+
+// getEntryTx returns principal and hash of pending tx of the queue entry: release tx of release entry,
+// or burn of the deposit of mint entry in lock mode. Returns empty hash if the entry has no tx
+func getEntryTx(a *accumulate.AccumulateClient, chainId int64, queue string, entryhash string) (string, string, error) {
+
+	entry, err := a.QueryDataEntry(&accumulate.Params{URL: accumulate.GenerateDataEntry(queue, entryhash)})
+	if err != nil {
+		return "", "", err
+	}
+
+	var principal, txHash string
+
+	if queue == accumulate.GenerateReleaseDataAccount(a.ADI, chainId, accumulate.ACC_RELEASE_QUEUE) {
+
+		// unparsable entry has no release tx
+		burnEntry, err := schema.ParseBurnEvent(entry.Data)
+		if err != nil || burnEntry.TxHash == "" {
+			return "", "", nil
+		}
+
+		token := utils.SearchEVMToken(burnEntry.TokenAddress)
+		if token == nil {
+			return "", "", fmt.Errorf("token %s of release tx %s not found", burnEntry.TokenAddress, burnEntry.TxHash)
+		}
+
+		principal = accumulate.GenerateTokenAccount(a.ADI, chainId, token.Symbol)
+		if token.IsLocked() {
+			principal = token.URL
+		}
+		txHash = burnEntry.TxHash
+
+	} else {
+
+		// unparsable entry has no burn tx
+		mintEntry, err := schema.ParseDepositEvent(entry.Data)
+		if err != nil || mintEntry.BurnTxHash == "" {
+			return "", "", nil
+		}
+
+		for _, token := range utils.GetTokens() {
+			if queue == accumulate.GenerateMintDataAccount(a.ADI, chainId, accumulate.ACC_MINT_QUEUE, token.Symbol) {
+				principal = accumulate.GenerateTokenAccount(a.ADI, chainId, token.Symbol)
+			}
+		}
+		if principal == "" {
+			return "", "", fmt.Errorf("token of mint queue %s not found", queue)
+		}
+		txHash = mintEntry.BurnTxHash
+
+	}
+
+	txid, err := acmeurl.ParseTxID(txHash)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid tx %s: %s", txHash, err)
+	}
+
+	hash := txid.Hash()
+
+	return principal, hex.EncodeToString(hash[:]), nil
+
+}
+
+// rejectEntryTx votes against pending tx of the queue entry. Executed or expired tx is left as is
+func rejectEntryTx(a *accumulate.AccumulateClient, chainId int64, queue string, entryhash string) error {
+
+	principal, txhash, err := getEntryTx(a, chainId, queue, entryhash)
+	if err != nil {
+		return err
+	}
+
+	if txhash == "" {
+		return nil
+	}
+
+	pending, err := isPendingTx(a, principal, txhash)
+	if err != nil {
+		return err
+	}
+
+	if !pending {
+		return nil
+	}
+
+	rejectTxHash, err := a.RejectTransaction(principal, txhash)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("[rejection] Rejected tx", txhash, "of", principal, "for entry", entryhash, "tx:", rejectTxHash)
+
+	return nil
+
+}
+
+// checkEntryTxPending checks that pending tx of the queue entry is neither executed nor expired, so the entry can be recovered
+func checkEntryTxPending(a *accumulate.AccumulateClient, chainId int64, queue string, entryhash string) error {
+
+	principal, txhash, err := getEntryTx(a, chainId, queue, entryhash)
+	if err != nil {
+		return fmt.Errorf("%s, manual recovery required", err)
+	}
+
+	if txhash == "" {
+		return nil
+	}
+
+	pending, err := isPendingTx(a, principal, txhash)
+	if err != nil {
+		return err
+	}
+
+	if !pending {
+		return fmt.Errorf("tx %s of %s is not pending, manual recovery required", txhash, principal)
+	}
+
+	return nil
+
+}
+
+// rejectInvalidEntry is called by auditors, when pending entry fails validation
+func rejectInvalidEntry(a *accumulate.AccumulateClient, chainId int64, rejections audit.Rejections, queue string, entryhash string, reason error) {
+
+	fmt.Println("[rejection] Entry", entryhash, "of", queue, "is invalid:", reason)
+
+	err := rejectEntry(a, chainId, rejections, queue, entryhash, nil, reason)
+	if err != nil {
+		fmt.Println("[rejection] Unable to reject entry", entryhash, err)
+	}
+
+}
+
+// isPendingTx checks if tx is in the pending chain of the account, that is it is neither executed nor expired
+func isPendingTx(a *accumulate.AccumulateClient, account string, txhash string) (bool, error) {
+
+	pending, err := a.QueryPendingChain(&accumulate.Params{URL: account})
+	if err != nil {
+		return false, err
+	}
+
+	for _, item := range pending.Items {
+		if strings.EqualFold(item, txhash) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+
+}
+
+// findPendingBurn returns txid of pending burn tx of the deposit, so the deposit is not burned twice if mint entry creation failed
+// Burns, rejected by the leader when their mint entries were recovered, are skipped
+func findPendingBurn(a *accumulate.AccumulateClient, tokenAccount string, mintEntry *schema.DepositEvent, amount int64, rejected map[string]bool) (string, error) {
+
+	account, err := acmeurl.Parse(tokenAccount)
+	if err != nil {
+		return "", err
+	}
+
+	skip := make(map[string]bool)
+	for burnTxHash := range rejected {
+		txid, err := acmeurl.ParseTxID(burnTxHash)
+		if err != nil {
+			continue
+		}
+		hash := txid.Hash()
+		skip[hex.EncodeToString(hash[:])] = true
+	}
+
+	pending, err := a.QueryPendingChain(&accumulate.Params{URL: tokenAccount})
+	if err != nil {
+		return "", err
+	}
+
+	for _, item := range pending.Items {
+
+		if skip[strings.ToLower(item)] {
+			continue
+		}
+
+		hash, err := hex.DecodeString(item)
+		if err != nil || len(hash) != 32 {
+			return "", fmt.Errorf("invalid pending tx hash %s", item)
+		}
+
+		var txhash [32]byte
+		copy(txhash[:], hash)
+		txid := account.WithTxID(txhash).String()
+
+		tx, err := a.QueryTokenTx(&accumulate.Params{URL: txid})
+		if err != nil {
+			return "", err
+		}
+
+		if tx.Type != accumulate.TX_TYPE_BURN_TOKENS || !strings.EqualFold(tx.Transaction.Header.Memo, mintEntry.TxID) {
+			continue
+		}
+
+		// burn of the deposit with different amount (e.g. fees changed) can not be paired with the mint
+		err = utils.ValidateDepositBurnTx(tx, mintEntry, amount)
+		if err != nil {
+			return "", fmt.Errorf("pending burn tx %s of the deposit, manual recovery required: %s", txid, err)
+		}
+
+		return txid, nil
+
+	}
+
+	return "", nil
+
+}
+
+// recoverReleaseEntry cancels the work of release entry, rejected by auditors, so the burn is processed again.
+// If the release tx of the entry is not pending anymore, the entry can not be recovered automatically
+func recoverReleaseEntry(a *accumulate.AccumulateClient, e *evm.EVMClient, rejections audit.Rejections, releaseQueue string, entryhash string) error {
+
+	// entry is recovered once
+	if rejections.HasRejected(hex.EncodeToString(a.PublicKeyHash), releaseQueue, entryhash) {
+		return nil
+	}
+
+	// executed release tx must not be sent again
+	err := checkEntryTxPending(a, int64(e.ChainId), releaseQueue, entryhash)
+	if err != nil {
+		return err
+	}
+
+	entry, err := a.QueryDataEntry(&accumulate.Params{URL: accumulate.GenerateDataEntry(releaseQueue, entryhash)})
+	if err != nil {
+		return err
+	}
+
+	// unparsable entry has no burn to process again
+	burnEntry, parseErr := schema.ParseBurnEvent(entry.Data)
+
+	// release tx is rejected together with the entry
+	err = rejectEntry(a, int64(e.ChainId), rejections, releaseQueue, entryhash, nil, errors.New("rejected by auditors, recovered by leader"))
+	if err != nil {
+		return err
+	}
+
+	// process the burn again, the scan is moved back only to the burn, burns skipped before it are not scanned again
+	if parseErr == nil && LatestCheckedEVMLog != nil && !LatestCheckedEVMLog.IsBefore(burnEntry.Position()) {
+		setLatestCheckedEVMLog(burnEntry.Previous())
+	}
+
+	return nil
+
+}
+
+// recoverMintEntry cancels the work of mint entry, rejected by auditors, so the deposit is processed again.
+// The new mint tx reuses the safe nonce of the rejected one, so only one of them can be executed.
+// If the nonce is used or the burn of the deposit is not pending anymore, the entry can not be recovered automatically
+func recoverMintEntry(a *accumulate.AccumulateClient, e *evm.EVMClient, safe *gnosis.SafeState, rejections audit.Rejections, token *schema.Token, mintQueue string, entryhash string) error {
+
+	// entry is recovered once
+	if rejections.HasRejected(hex.EncodeToString(a.PublicKeyHash), mintQueue, entryhash) {
+		return nil
 	}
 
-	log.Info("duplicate token ", token.URL, ", overwritten")
-	*exists = *token
+	entry, err := a.QueryDataEntry(&accumulate.Params{URL: accumulate.GenerateDataEntry(mintQueue, entryhash)})
+	if err != nil {
+		return err
+	}
+
+	// unparsable entry has no mint tx
+	mintEntry, err := schema.ParseDepositEvent(entry.Data)
+	if err == nil {
+
+		if mintEntry.SafeTxNonce < safe.Nonce {
+			return fmt.Errorf("safe nonce %d of mint tx is used, manual recovery required", mintEntry.SafeTxNonce)
+		}
+
+		if mintEntry.SafeTxNonce > safe.Nonce {
+			return fmt.Errorf("mint tx nonce %d is after safe nonce %d, waiting for previous txs", mintEntry.SafeTxNonce, safe.Nonce)
+		}
+
+	}
+
+	// executed burn must not be sent again
+	err = checkEntryTxPending(a, int64(e.ChainId), mintQueue, entryhash)
+	if err != nil {
+		return err
+	}
+
+	// burn tx is rejected together with the entry
+	err = rejectEntry(a, int64(e.ChainId), rejections, mintQueue, entryhash, mintEntry, errors.New("rejected by auditors, recovered by leader"))
+	if err != nil {
+		return err
+	}
+
+	// process the deposit again
+	if mintEntry != nil && LatestCheckedDeposits[token.Symbol] >= mintEntry.SeqNumber {
+		LatestCheckedDeposits[token.Symbol] = mintEntry.SeqNumber - 1
+	}
+
+	return nil
 
 }
 
@@ -445,8 +1432,65 @@ func debugLeader(die chan bool) {
 
 }
 
+// skippedLog returns position of burn log, which is not released
+func skippedLog(l *evm.EventLog) *schema.BurnEvent {
+	return &schema.BurnEvent{EVMTxID: l.TxID.Hex(), BlockHeight: int64(l.BlockHeight), BlockHash: l.BlockHash.Hex(), LogIndex: uint64(l.LogIndex)}
+}
+
+// setLatestCheckedEVMLog moves burn logs scan progress and saves it, so the node does not scan processed logs again after restart
+func setLatestCheckedEVMLog(event *schema.BurnEvent) {
+
+	LatestCheckedEVMLog = event
+
+	if ScanProgressFile == "" {
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		fmt.Println("[release] can not save scan progress:", err)
+		return
+	}
+
+	tmp := ScanProgressFile + ".tmp"
+	if err = ioutil.WriteFile(tmp, data, 0600); err != nil {
+		fmt.Println("[release] can not save scan progress:", err)
+		return
+	}
+
+	if err = os.Rename(tmp, ScanProgressFile); err != nil {
+		fmt.Println("[release] can not save scan progress:", err)
+	}
+
+}
+
+// loadScanProgress restores burn logs scan progress saved by setLatestCheckedEVMLog
+func loadScanProgress() error {
+
+	LatestCheckedEVMLog = nil
+
+	data, err := ioutil.ReadFile(ScanProgressFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("can not read scan progress: %s", err)
+	}
+
+	if err = json.Unmarshal(data, &LatestCheckedEVMLog); err != nil {
+		return fmt.Errorf("can not parse scan progress %s: %s", ScanProgressFile, err)
+	}
+
+	if LatestCheckedEVMLog != nil {
+		fmt.Println("Burn logs scanned up to blockHeight", LatestCheckedEVMLog.BlockHeight)
+	}
+
+	return nil
+
+}
+
 // processBurnEvents
-func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge string, die chan bool) {
+func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge string, safe string, die chan bool) {
 
 	for {
 
@@ -469,8 +1513,34 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 						break
 					}
 
-					// if there are any pending entries, do not produce new tx
-					if len(pendingEntries.Items) > 0 {
+					rejections, err := getRejections(a, int64(e.ChainId))
+					if err != nil {
+						fmt.Println("[release] Stopping the process, unable to get rejections:", err)
+						break
+					}
+
+					rejected, err := getRejectedEntries(a, rejections, releaseQueue, pendingEntries.Items)
+					if err != nil {
+						fmt.Println("[release] Stopping the process, unable to check rejections:", err)
+						break
+					}
+
+					// entries rejected by auditors are recovered, if there are any other pending entries, do not produce new tx
+					blocked := false
+					for _, entryhash := range pendingEntries.Items {
+						if !rejected[entryhash] {
+							blocked = true
+							break
+						}
+						err = recoverReleaseEntry(a, e, rejections, releaseQueue, entryhash)
+						if err != nil {
+							fmt.Println("[release] Unable to recover rejected entry", entryhash, err)
+							blocked = true
+							break
+						}
+					}
+
+					if blocked {
 						fmt.Println("[release] Stopping the process, found pending entries in", releaseQueue)
 						break
 					}
@@ -484,21 +1554,40 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 						break
 					}
 
-					// parse latest burn entry to find out evm blockHeight
-					burnEntry, err := schema.ParseBurnEvent(latestReleaseEntry.Data)
+					// parse latest burn entry to find out evm blockHeight and log index
+					cursor, err := schema.ParseBurnEvent(latestReleaseEntry.Data)
 					if err != nil {
 						fmt.Println("[release]", err)
 						break
 					}
 
-					// looking for evm logs starting from latest height+1
-					start := burnEntry.BlockHeight + 1
-					if LatestCheckedEVMHeight > burnEntry.BlockHeight {
-						start = LatestCheckedEVMHeight + 1
+					if LatestCheckedEVMLog != nil && cursor.IsBefore(LatestCheckedEVMLog.Position()) {
+						cursor = LatestCheckedEVMLog
+					}
+
+					// looking for evm logs after the latest processed one
+					start := cursor.BlockHeight
+					if cursor.BlockHash == "" {
+						start++
 					}
 
-					fmt.Println("[release] Parsing new EVM events for", bridge, "starting from blockHeight", start)
-					logs, err := e.ParseBridgeLogs("Burn", bridge, &evm.BlockRange{From: start})
+					fmt.Println("[release] Parsing new EVM events for", bridge, "and locks in", safe, "starting from blockHeight", start)
+
+					// scan blocks in chunks until the first chunk with new events
+					var logs []*evm.EventLog
+					err = e.ScanReleaseLogs(bridge, safe, utils.GetLockedTokens(), &evm.BlockRange{From: start}, func(scanned *evm.BlockRange, chunkLogs []*evm.EventLog) error {
+						for _, l := range chunkLogs {
+							if cursor.IsBefore(int64(l.BlockHeight), uint64(l.LogIndex)) {
+								logs = append(logs, l)
+							}
+						}
+						if len(logs) > 0 {
+							return evm.ErrStopScan
+						}
+						// no new events in the chunk, next batch starts after it
+						setLatestCheckedEVMLog(&schema.BurnEvent{BlockHeight: scanned.To})
+						return nil
+					})
 					if err != nil {
 						fmt.Println("[release]", err)
 						break
@@ -506,14 +1595,14 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 
 					knownHeight := 0
 
-					// logs are sorted by timestamp asc
+					// logs are sorted by block height and log index asc
 					for _, l := range logs {
 
-						fmt.Println("[release] Height", l.BlockHeight, "txid", l.TxID.Hex())
+						fmt.Println("[release] Height", l.BlockHeight, "txid", l.TxID.Hex(), "log index", l.LogIndex)
 
 						// additional check in case evm node returns invalid response
-						if int64(l.BlockHeight) < start {
-							fmt.Println("[release] Invalid height, expected height >=", start)
+						if !cursor.IsBefore(int64(l.BlockHeight), uint64(l.LogIndex)) {
+							fmt.Println("[release] Invalid log position, expected log after height", cursor.BlockHeight, "log index", cursor.LogIndex)
 							continue
 						}
 
@@ -524,10 +1613,19 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 							break
 						}
 
+						// stale leader stops before sending tokens, the new leader processes the event in its term
+						if !global.HoldsLease() {
+							fmt.Println("[release] Leader lease expired, stopping the process")
+							break
+						}
+
 						// create burnEntry
 						burnEntry := &schema.BurnEvent{}
+						burnEntry.Term = global.LeaderTerm
 						burnEntry.EVMTxID = l.TxID.Hex()
 						burnEntry.BlockHeight = int64(l.BlockHeight)
+						burnEntry.BlockHash = l.BlockHash.Hex()
+						burnEntry.LogIndex = uint64(l.LogIndex)
 						burnEntry.TokenAddress = l.Token.String()
 						burnEntry.Destination = l.Destination
 						burnEntry.Amount = l.Amount.Int64()
@@ -535,11 +1633,33 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 						// find token
 						token := utils.SearchEVMToken(burnEntry.TokenAddress)
 
-						// skip if no token found
+						// skip if no token found, skipped log is not scanned again
 						if token == nil {
+							fmt.Println("[release] Unknown token", burnEntry.TokenAddress, "skipping log")
+							setLatestCheckedEVMLog(skippedLog(l))
+							continue
+						}
+
+						// tokens in lock mode are released only for transfers to the safe, wrapped tokens only for burns
+						if token.IsLocked() != l.Locked {
+							fmt.Println("[release] Token", token.Symbol, "mode", token.Mode, "does not match event, skipping log")
+							setLatestCheckedEVMLog(skippedLog(l))
 							continue
 						}
 
+						// verify burn tx receipt
+						burnTx, err := e.GetTx(burnEntry.EVMTxID)
+						if err != nil {
+							fmt.Println("[release] Unable to get burn tx, will process event in the next batch:", err)
+							break
+						}
+
+						err = utils.ValidateBurnTx(burnTx, l)
+						if err != nil {
+							fmt.Println("[release] Burn tx validation failed, will process event in the next batch:", err)
+							break
+						}
+
 						operation := &fees.Operation{
 							Token:  token,
 							Amount: l.Amount.Int64(),
@@ -548,23 +1668,54 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 						outAmount, err := operation.ApplyFees(&global.BridgeFees, fees.OP_RELEASE)
 						// skip if output amount is invalid (too low or negative, e.g.)
 						if err != nil {
+							fmt.Println("[release] Invalid amount, skipping log:", err)
+							setLatestCheckedEVMLog(skippedLog(l))
 							continue
 						}
 
 						outAmountHuman := float64(outAmount) / math.Pow10(int(token.Precision))
 
-						fmt.Println("[release] Sending", outAmountHuman, token.Symbol, "to", burnEntry.Destination)
+						// validate destination before sending tokens
+						err = utils.ValidateDestination(a, burnEntry.Destination, token.URL)
+						if err != nil && !errors.Is(err, utils.ErrInvalidDestination) {
+							fmt.Println("[release] Unable to validate destination, will process event in the next batch:", err)
+							break
+						}
 
-						// generate accumulate token tx
-						txhash, err := a.SendTokens(burnEntry.Destination, outAmount, token.URL, int64(e.ChainId))
 						if err != nil {
-							fmt.Println("[release] tx failed:", err)
-							continue
-						}
 
-						fmt.Println("[release] tx sent:", txhash)
+							// invalid destination can not receive tokens
+							// burn is recorded as exception in the release queue instead of being released
+							fmt.Println("[release] Invalid destination, not sending", outAmountHuman, token.Symbol, "to", burnEntry.Destination, err)
+							burnEntry.Exception = err.Error()
+
+						} else {
+
+							fmt.Println("[release] Sending", outAmountHuman, token.Symbol, "to", burnEntry.Destination)
+
+							// generate accumulate token tx with reference to the burn
+							// EVM-native tokens are issued by the bridge, others are sent from the bridge token account
+							memo := accumulate.GenerateReleaseMemo(int64(e.ChainId), burnEntry.EVMTxID, uint64(l.LogIndex))
+							var txhash string
+							if token.IsLocked() {
+								txhash, err = a.IssueTokens(burnEntry.Destination, outAmount, token.URL, memo)
+							} else {
+								txhash, err = a.SendTokens(burnEntry.Destination, outAmount, token.URL, int64(e.ChainId), memo)
+							}
+							if err != nil {
+								fmt.Println("[release] tx failed, will process event in the next batch:", err)
+								break
+							}
+
+							fmt.Println("[release] tx sent:", txhash)
+
+							burnEntry.TxHash = txhash
 
-						burnEntry.TxHash = txhash
+						}
+
+						// burn is processed and is not sent again, even if data entry creation fails
+						knownHeight = int(l.BlockHeight)
+						setLatestCheckedEVMLog(burnEntry)
 
 						burnEntryBytes, err := json.Marshal(burnEntry)
 						if err != nil {
@@ -584,10 +1735,6 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 
 						fmt.Println("[release] data entry created:", entryhash)
 
-						knownHeight = int(l.BlockHeight)
-
-						LatestCheckedEVMHeight = int64(l.BlockHeight)
-
 					}
 
 				} else if global.IsAudit {
@@ -622,11 +1769,28 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 						break
 					}
 
-					// looking for pending tx with blockheight starting from latest height+1
-					start := latestCompletedBurn.BlockHeight + 1
+					rejections, err := getRejections(a, int64(e.ChainId))
+					if err != nil {
+						fmt.Println("[release] Unable to get rejections:", err)
+						break
+					}
+
+					// entries, which can not reach the threshold, are recovered by the leader, their txs are not signed anymore
+					rejected, err := getRejectedEntries(a, rejections, releaseQueue, pending.Items)
+					if err != nil {
+						fmt.Println("[release] Unable to check rejected entries:", err)
+						break
+					}
+
+					// looking for pending burns after the latest completed one
 
 					for _, entryhash := range pending.Items {
 
+						// rejected entries are not validated again
+						if rejected[entryhash] || rejections.HasRejected(hex.EncodeToString(a.PublicKeyHash), releaseQueue, entryhash) {
+							continue
+						}
+
 						fmt.Println("[release] processing pending entry", entryhash)
 
 						entryURL := entryhash + "@" + releaseQueue
@@ -638,15 +1802,26 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 
 						burnEntry, err := schema.ParseBurnEvent(entry.Data)
 						if err != nil {
-							fmt.Println("[release] Unable to parse burn event from data entry", err)
+							rejectInvalidEntry(a, int64(e.ChainId), rejections, releaseQueue, entryhash, fmt.Errorf("unable to parse burn event: %s", err))
 							continue
 						}
 
-						fmt.Println("[release] start", start, "event blockheight", burnEntry.BlockHeight)
+						fmt.Println("[release] latest height", latestCompletedBurn.BlockHeight, "log index", latestCompletedBurn.LogIndex, "event height", burnEntry.BlockHeight, "log index", burnEntry.LogIndex)
 
-						// check block height to avoid old txs
-						if int64(burnEntry.BlockHeight) < start {
-							fmt.Println("[release] Invalid height, expected height >=", start)
+						// check block height and log index to avoid old txs
+						if !latestCompletedBurn.IsBefore(burnEntry.Position()) {
+							rejectInvalidEntry(a, int64(e.ChainId), rejections, releaseQueue, entryhash, fmt.Errorf("invalid log position, expected log after height %d log index %d", latestCompletedBurn.BlockHeight, latestCompletedBurn.LogIndex))
+							continue
+						}
+
+						// entries of a stale leader are rejected, entries of a newer term wait until this node sees the term,
+						// entries without term were queued before the upgrade and are valid in any term
+						if burnEntry.Term != 0 && burnEntry.Term < global.LeaderTerm {
+							rejectInvalidEntry(a, int64(e.ChainId), rejections, releaseQueue, entryhash, fmt.Errorf("invalid entry term %d, expected leader term %d", burnEntry.Term, global.LeaderTerm))
+							continue
+						}
+						if burnEntry.Term != 0 && burnEntry.Term != global.LeaderTerm {
+							fmt.Println("[release] Invalid entry term", burnEntry.Term, "expected leader term", global.LeaderTerm)
 							continue
 						}
 
@@ -664,33 +1839,76 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 						// Valid burn txs, created by other contracts, calling Accumulate Bridge contract, are invalidated in this case
 						// It's safe to just validate Accumulate Bridge smart contract burn events
 
-						fmt.Println("[release] Parsing EVM events for", bridge, "at blockHeight", burnEntry.BlockHeight)
-						logs, err := e.ParseBridgeLogs("Burn", bridge, &evm.BlockRange{From: burnEntry.BlockHeight, To: burnEntry.BlockHeight})
+						fmt.Println("[release] Parsing EVM events for", bridge, "and locks in", safe, "at blockHeight", burnEntry.BlockHeight)
+						logs, err := e.ParseReleaseLogs(bridge, safe, utils.GetLockedTokens(), &evm.BlockRange{From: burnEntry.BlockHeight, To: burnEntry.BlockHeight})
 						if err != nil {
 							fmt.Println("[release]", err)
 							break
 						}
 
-						foundLog := &evm.EventLog{}
-
-						// find only one log, associated with txid
-						for _, l := range logs {
-							if l.TxID.String() == burnEntry.EVMTxID {
-								foundLog = l
-							}
+						// find the log, associated with txid and log index
+						foundLog := utils.FindBurnLog(logs, burnEntry)
+						if foundLog == nil {
+							fmt.Println("[release] Burn log", burnEntry.EVMTxID, "log index", burnEntry.LogIndex, "not found at blockHeight", burnEntry.BlockHeight)
+							continue
 						}
 
 						// validate burn entry against evm log
 						err = utils.ValidateBurnEntry(burnEntry, foundLog)
 						if err != nil {
-							fmt.Println("[release] burn entry validation failed:", err)
+							rejectInvalidEntry(a, int64(e.ChainId), rejections, releaseQueue, entryhash, fmt.Errorf("burn entry validation failed: %s", err))
+							continue
+						}
+
+						if token.IsLocked() != foundLog.Locked {
+							rejectInvalidEntry(a, int64(e.ChainId), rejections, releaseQueue, entryhash, fmt.Errorf("token %s in %s mode can not be released for the event", token.Symbol, token.Mode))
+							continue
+						}
+
+						// verify burn tx receipt
+						burnTx, err := e.GetTx(burnEntry.EVMTxID)
+						if err != nil {
+							fmt.Println("[release] Unable to get burn tx:", err)
 							continue
 						}
 
+						err = utils.ValidateBurnTx(burnTx, foundLog)
+						if err != nil {
+							rejectInvalidEntry(a, int64(e.ChainId), rejections, releaseQueue, entryhash, fmt.Errorf("burn tx validation failed: %s", err))
+							continue
+						}
+
+						// burn recorded as exception: sign data entry only if destination is invalid indeed
+						if burnEntry.Exception != "" {
+
+							err = utils.ValidateDestination(a, burnEntry.Destination, token.URL)
+							if err == nil {
+								rejectInvalidEntry(a, int64(e.ChainId), rejections, releaseQueue, entryhash, fmt.Errorf("exception entry has valid destination %s", burnEntry.Destination))
+								continue
+							}
+							if !errors.Is(err, utils.ErrInvalidDestination) {
+								fmt.Println("[release] Unable to validate destination:", err)
+								continue
+							}
+
+							fmt.Println("[release] Confirming exception:", err)
+
+							// sign data entry
+							txhash, err := a.RemoteTransaction(releaseQueue, entryhash)
+							if err != nil {
+								fmt.Println("[release] tx failed:", err)
+								continue
+							}
+
+							fmt.Println("[release] tx sent:", txhash)
+							continue
+
+						}
+
 						// parse accumulate txid
 						txid, err := acmeurl.ParseTxID(burnEntry.TxHash)
 						if err != nil {
-							fmt.Println(err)
+							rejectInvalidEntry(a, int64(e.ChainId), rejections, releaseQueue, entryhash, fmt.Errorf("invalid release tx: %s", err))
 							continue
 						}
 
@@ -704,15 +1922,18 @@ func processBurnEvents(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge
 						}
 
 						// validate accumulate tx against evm tx
-						err = utils.ValidateReleaseTx(tx.Data, foundLog)
+						err = utils.ValidateReleaseTx(tx, foundLog, int64(e.ChainId))
 						if err != nil {
-							fmt.Println("[release] accumulate tx validation failed:", err)
+							rejectInvalidEntry(a, int64(e.ChainId), rejections, releaseQueue, entryhash, fmt.Errorf("accumulate tx validation failed: %s", err))
 							continue
 						}
 
-						// sign accumulate tx
-						tokenAccount := accumulate.GenerateTokenAccount(a.ADI, int64(e.ChainId), token.Symbol)
-						txhash, err := a.RemoteTransaction(tokenAccount, hex.EncodeToString(remoteTxHash[:]))
+						// sign accumulate tx, issued by token issuer or sent from bridge token account
+						principal := accumulate.GenerateTokenAccount(a.ADI, int64(e.ChainId), token.Symbol)
+						if token.IsLocked() {
+							principal = token.URL
+						}
+						txhash, err := a.RemoteTransaction(principal, hex.EncodeToString(remoteTxHash[:]))
 						if err != nil {
 							fmt.Println("[release] tx failed:", err)
 							continue
@@ -756,32 +1977,54 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 
 				if global.IsLeader {
 
-					for _, token := range global.Tokens.Items {
+					for _, token := range utils.GetTokens() {
 
-						// get gnosis safe
-						safe, err := g.GetSafe()
+						// get gnosis safe state from the chain, cross-checked with gnosis safe api
+						safe, err := g.GetSafeState()
 						if err != nil {
 							fmt.Println("[mint] can not get gnosis safe:", err)
 							break
 						}
 
-						nonce, err := strconv.ParseInt(safe.Nonce, 10, 64)
+						nonce := safe.Nonce
+
+						// check if there are pending txs at current or future nonces
+						safeTxs, err := utils.GetSignedSafeTxs(a, g, safe)
 						if err != nil {
-							fmt.Println("[mint] can not parse int from nonce string:", err)
+							fmt.Println("[mint] can not get gnosis safe multisig txs:", err)
 							break
 						}
 
-						// check if there are pending txs at current nonce
-						safeTxs, err := g.GetSafeMultisigTxs()
+						rejections, err := getRejections(a, int64(e.ChainId))
 						if err != nil {
-							fmt.Println("[mint] can not get gnosis safe multisig txs:", err)
+							fmt.Println("[mint] can not get rejections:", err)
 							break
 						}
 
-						if len(safeTxs.Results) > 0 {
-							if safeTxs.Results[0].Nonce >= nonce {
-								fmt.Println("[mint] stopping the process, gnosis safe has unprocessed tx with nonce", safeTxs.Results[0].Nonce)
-								break
+						// txs of mint entries, recovered by this node, are replaced by new txs at the same nonce
+						recovered := rejections.SafeTxHashes(hex.EncodeToString(a.PublicKeyHash))
+						var unprocessed []*gnosis.MultisigTx
+						for _, tx := range safeTxs {
+							if tx.Nonce != safe.Nonce || !recovered[strings.ToLower(tx.SafeTxHash)] {
+								unprocessed = append(unprocessed, tx)
+							}
+						}
+
+						if len(unprocessed) > 0 {
+							fmt.Println("[mint] stopping the process, gnosis safe has", len(unprocessed), "unprocessed txs from nonce", unprocessed[0].Nonce)
+							break
+						}
+
+						// minting paused token reverts, deposits are processed after unpause
+						if !token.IsLocked() {
+							paused, err := e.IsPaused(token.EVMAddress)
+							if err != nil {
+								fmt.Println("[mint] can not check if token is paused:", err)
+								continue
+							}
+							if paused {
+								fmt.Println("[mint] Skipping", token.Symbol, "token is paused on EVM")
+								continue
 							}
 						}
 
@@ -795,8 +2038,28 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 							continue
 						}
 
-						// if there are any pending entries, do not produce new tx
-						if len(pendingEntries.Items) > 0 {
+						rejected, err := getRejectedEntries(a, rejections, mintQueue, pendingEntries.Items)
+						if err != nil {
+							fmt.Println("[mint] Stopping the process, unable to check rejections:", err)
+							continue
+						}
+
+						// entries rejected by auditors are recovered, if there are any other pending entries, do not produce new tx
+						blocked := false
+						for _, entryhash := range pendingEntries.Items {
+							if !rejected[entryhash] {
+								blocked = true
+								break
+							}
+							err = recoverMintEntry(a, e, safe, rejections, token, mintQueue, entryhash)
+							if err != nil {
+								fmt.Println("[mint] Unable to recover rejected entry", entryhash, err)
+								blocked = true
+								break
+							}
+						}
+
+						if blocked {
 							fmt.Println("[mint] Stopping the process, found pending entries in", mintQueue)
 							continue
 						}
@@ -891,8 +2154,16 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 									continue
 								}
 
+								// stale leader stops before signing and burning, the new leader processes the deposit in its term
+								if !global.HoldsLease() {
+									fmt.Println("[mint] Leader lease expired, stopping the process")
+									cursor = start - 1
+									break
+								}
+
 								// create mintEntry
 								mintEntry := &schema.DepositEvent{}
+								mintEntry.Term = global.LeaderTerm
 								mintEntry.Amount = amount.Int64()
 								mintEntry.Destination = cause.Transaction.Header.Memo
 								mintEntry.SeqNumber = cursor
@@ -918,18 +2189,8 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 
 								// outAmountHuman := float64(outAmount) / math.Pow10(int(token.Precision))
 
-								// generate mint tx data
-								data, err := abiutil.GenerateMintTxData(token.EVMAddress, cause.Transaction.Header.Memo, outAmountBigInt)
-								if err != nil {
-									fmt.Println("[mint] can not generate mint tx:", err)
-									// if we are here, then something unexpected happened
-									// reset cursor and break to start over
-									cursor = start - 1
-									break
-								}
-
 								// generate gnosis safe tx
-								contractHash, signature, err := g.SignMintTx(token.EVMAddress, cause.Transaction.Header.Memo, outAmountBigInt)
+								safeTx, err := signDepositTx(g, token, cause.Transaction.Header.Memo, outAmountBigInt, nonce)
 								if err != nil {
 									fmt.Println("[mint] can not sign mint tx:", err)
 									// if we are here, then something unexpected happened
@@ -938,28 +2199,37 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 									break
 								}
 
-								// submit multisig tx to the gnosis safe api
-								safeTx := gnosis.NewMultisigTx{}
-								safeTx.To = g.BridgeAddress
-								safeTx.Data = hexutil.Encode(data)
-								safeTx.GasToken = abiutil.ZERO_ADDR
-								safeTx.RefundReceiver = abiutil.ZERO_ADDR
-								safeTx.Nonce = nonce
-								safeTx.ContractTransactionHash = hexutil.Encode(contractHash)
-								safeTx.Sender = g.PublicKey.Hex()
-								safeTx.Signature = hexutil.Encode(signature)
-
-								err = g.CreateSafeMultisigTx(&safeTx)
+								// submit signed tx to the gnosis safe api and/or accumulate signatures data account
+								err = utils.ShareSignature(a, g, safe, safeTx, mintEntry.TxID, mintEntry.Term)
 								if err != nil {
-									fmt.Println("[mint] gnosis safe api error:", err)
-									// if we are here, then something happened on the gnosis api side
+									fmt.Println("[mint] can not share signature:", err)
+									// if we are here, then something happened on the gnosis or accumulate api side
 									// reset cursor and break to start over
 									cursor = start - 1
 									break
 								}
 
+								// tokens in lock mode are unlocked on EVM, so deposited tokens are burned on Accumulate
+								// burn tx is pending until auditors sign it
+								// burn references the deposit, so burn of the previous attempt is reused instead of burning twice
+								if token.IsLocked() {
+									burnTxHash, err := findPendingBurn(a, tokenAccount, mintEntry, outAmount, rejections.BurnTxHashes(hex.EncodeToString(a.PublicKeyHash)))
+									if err == nil && burnTxHash == "" {
+										burnTxHash, err = a.BurnTokens(tokenAccount, outAmount, tx.TxID)
+									}
+									if err != nil {
+										fmt.Println("[mint] can not burn deposited tokens:", err)
+										// if we are here, then something happened on the accumulate api side
+										// reset cursor and break to start over
+										cursor = start - 1
+										break
+									}
+									fmt.Println("[mint] burn tx:", burnTxHash)
+									mintEntry.BurnTxHash = burnTxHash
+								}
+
 								// create accumulate data entry
-								mintEntry.SafeTxHash = hexutil.Encode(contractHash)
+								mintEntry.SafeTxHash = safeTx.ContractTransactionHash
 								mintEntry.SafeTxNonce = nonce
 
 								mintEntryBytes, err := json.Marshal(mintEntry)
@@ -1002,19 +2272,28 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 
 				} else if global.IsAudit {
 
-					for _, token := range global.Tokens.Items {
+					for _, token := range utils.GetTokens() {
 
-						// get gnosis safe
-						safe, err := g.GetSafe()
+						// get gnosis safe state from the chain, cross-checked with gnosis safe api
+						safe, err := g.GetSafeState()
 						if err != nil {
 							fmt.Println("[mint] can not get gnosis safe:", err)
 							break
 						}
 
-						nonce, err := strconv.ParseInt(safe.Nonce, 10, 64)
-						if err != nil {
-							fmt.Println("[mint] can not parse int from nonce string:", err)
-							break
+						nonce := safe.Nonce
+
+						// do not sign mints of paused token
+						if !token.IsLocked() {
+							paused, err := e.IsPaused(token.EVMAddress)
+							if err != nil {
+								fmt.Println("[mint] can not check if token is paused:", err)
+								continue
+							}
+							if paused {
+								fmt.Println("[mint] Skipping", token.Symbol, "token is paused on EVM")
+								continue
+							}
 						}
 
 						mintQueue := accumulate.GenerateMintDataAccount(a.ADI, int64(e.ChainId), accumulate.ACC_MINT_QUEUE, token.Symbol)
@@ -1050,11 +2329,29 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 							break
 						}
 
+						rejections, err := getRejections(a, int64(e.ChainId))
+						if err != nil {
+							fmt.Println("[mint] Unable to get rejections:", err)
+							break
+						}
+
+						// entries, which can not reach the threshold, are recovered by the leader, their txs are not signed anymore
+						rejected, err := getRejectedEntries(a, rejections, mintQueue, pending.Items)
+						if err != nil {
+							fmt.Println("[mint] Unable to check rejected entries:", err)
+							break
+						}
+
 						// looking for pending tx with sequence number starting from latest seq number+1
 						start := latestCompletedMint.SeqNumber + 1
 
 						for _, entryhash := range pending.Items {
 
+							// rejected entries are not validated again
+							if rejected[entryhash] || rejections.HasRejected(hex.EncodeToString(a.PublicKeyHash), mintQueue, entryhash) {
+								continue
+							}
+
 							fmt.Println("[mint] processing pending entry", entryhash)
 
 							entryURL := entryhash + "@" + mintQueue
@@ -1066,7 +2363,7 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 
 							mintEntry, err := schema.ParseDepositEvent(entry.Data)
 							if err != nil {
-								fmt.Println("[mint] Unable to parse deposit event from data entry", err)
+								rejectInvalidEntry(a, int64(e.ChainId), rejections, mintQueue, entryhash, fmt.Errorf("unable to parse deposit event: %s", err))
 								continue
 							}
 
@@ -1074,7 +2371,18 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 
 							// check block height to avoid old txs
 							if int64(mintEntry.SeqNumber) < start {
-								fmt.Println("[mint] Invalid seq number, expected seq number >=", start)
+								rejectInvalidEntry(a, int64(e.ChainId), rejections, mintQueue, entryhash, fmt.Errorf("invalid seq number %d, expected seq number >= %d", mintEntry.SeqNumber, start))
+								continue
+							}
+
+							// entries of a stale leader are rejected, entries of a newer term wait until this node sees the term,
+							// entries without term were queued before the upgrade and are valid in any term
+							if mintEntry.Term != 0 && mintEntry.Term < global.LeaderTerm {
+								rejectInvalidEntry(a, int64(e.ChainId), rejections, mintQueue, entryhash, fmt.Errorf("invalid entry term %d, expected leader term %d", mintEntry.Term, global.LeaderTerm))
+								continue
+							}
+							if mintEntry.Term != 0 && mintEntry.Term != global.LeaderTerm {
+								fmt.Println("[mint] Invalid entry term", mintEntry.Term, "expected leader term", global.LeaderTerm)
 								continue
 							}
 
@@ -1094,6 +2402,7 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 								fmt.Println("[mint] mint entry tx:", mintEntry.TxID)
 								// validate txid in mint entry
 								if txs.Items[0].TxID != mintEntry.TxID {
+									rejectInvalidEntry(a, int64(e.ChainId), rejections, mintQueue, entryhash, fmt.Errorf("mint entry txid %s does not match tx %s with seq number %d", mintEntry.TxID, txs.Items[0].TxID, mintEntry.SeqNumber))
 									continue
 								}
 							} else {
@@ -1111,18 +2420,22 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 							// validate cause tx
 							err = utils.ValidateCauseTx(cause)
 							if err != nil {
-								fmt.Println("[mint] cause tx validation failed:", err)
+								rejectInvalidEntry(a, int64(e.ChainId), rejections, mintQueue, entryhash, fmt.Errorf("cause tx validation failed: %s", err))
 								continue
 							}
 
 							// validate mint entry against accumulate txs
 							err = utils.ValidateMintEntry(mintEntry, txs.Items[0], cause)
 							if err != nil {
-								fmt.Println("[mint] accumulate tx validation failed:", err)
+								rejectInvalidEntry(a, int64(e.ChainId), rejections, mintQueue, entryhash, fmt.Errorf("accumulate tx validation failed: %s", err))
 								continue
 							}
 
-							// check mint entry safe tx nonce
+							// check mint entry safe tx nonce, used nonce can not be signed anymore
+							if mintEntry.SafeTxNonce < nonce {
+								rejectInvalidEntry(a, int64(e.ChainId), rejections, mintQueue, entryhash, fmt.Errorf("safe tx nonce %d is used, safe nonce %d", mintEntry.SafeTxNonce, nonce))
+								continue
+							}
 							if mintEntry.SafeTxNonce != nonce {
 								fmt.Println("[mint] mint entry safe tx nonce:", mintEntry.SafeTxNonce, "safe nonce:", safe.Nonce)
 								continue
@@ -1140,49 +2453,68 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 								continue
 							}
 
-							// generate mint tx data
-							amount := big.NewInt(outAmount)
-							data, err := abiutil.GenerateMintTxData(token.EVMAddress, cause.Transaction.Header.Memo, amount)
-							if err != nil {
-								fmt.Println("[mint] can not generate mint tx:", err)
-								continue
-							}
-
 							// generate gnosis safe tx
-							contractHash, signature, err := g.SignMintTx(token.EVMAddress, cause.Transaction.Header.Memo, amount)
+							amount := big.NewInt(outAmount)
+							safeTx, err := signDepositTx(g, token, cause.Transaction.Header.Memo, amount, nonce)
 							if err != nil {
 								fmt.Println("[mint] can not sign mint tx:", err)
 								continue
 							}
 
 							// check if contract hash == mint entry safetxhash
-							if hexutil.Encode(contractHash) != mintEntry.SafeTxHash {
-								fmt.Println("[mint] mint entry safe tx hash:", mintEntry.SafeTxHash, "generated safe tx hash:", hexutil.Encode(contractHash))
+							if safeTx.ContractTransactionHash != mintEntry.SafeTxHash {
 								fmt.Println("[debug] token address:", token.EVMAddress)
 								fmt.Println("[debug] memo:", cause.Transaction.Header.Memo)
 								fmt.Println("[debug] amount:", amount)
+								rejectInvalidEntry(a, int64(e.ChainId), rejections, mintQueue, entryhash, fmt.Errorf("mint entry safe tx hash %s does not match generated safe tx hash %s", mintEntry.SafeTxHash, safeTx.ContractTransactionHash))
 								continue
 							}
 
-							// submit multisig tx to the gnosis safe api
-							safeTx := gnosis.NewMultisigTx{}
-							safeTx.To = g.BridgeAddress
-							safeTx.Data = hexutil.Encode(data)
-							safeTx.GasToken = abiutil.ZERO_ADDR
-							safeTx.RefundReceiver = abiutil.ZERO_ADDR
-							safeTx.Nonce = nonce
-							safeTx.ContractTransactionHash = hexutil.Encode(contractHash)
-							safeTx.Sender = g.PublicKey.Hex()
-							safeTx.Signature = hexutil.Encode(signature)
-
-							err = g.CreateSafeMultisigTx(&safeTx)
+							// tokens in lock mode: validate burn of deposited tokens before signing anything
+							var burnTxHash [32]byte
+							if token.IsLocked() {
+
+								burnTx, err := a.QueryTokenTx(&accumulate.Params{URL: mintEntry.BurnTxHash})
+								if err != nil {
+									fmt.Println("[mint] can not get burn tx:", err)
+									continue
+								}
+
+								err = utils.ValidateDepositBurnTx(burnTx, mintEntry, outAmount)
+								if err != nil {
+									rejectInvalidEntry(a, int64(e.ChainId), rejections, mintQueue, entryhash, fmt.Errorf("burn tx validation failed: %s", err))
+									continue
+								}
+
+								burnTxID, err := acmeurl.ParseTxID(mintEntry.BurnTxHash)
+								if err != nil {
+									fmt.Println("[mint]", err)
+									continue
+								}
+
+								burnTxHash = burnTxID.Hash()
+
+							}
+
+							// submit signed tx to the gnosis safe api and/or accumulate signatures data account
+							err = utils.ShareSignature(a, g, safe, safeTx, mintEntry.TxID, mintEntry.Term)
 							if err != nil {
-								fmt.Println("[mint] gnosis safe api error:", err)
+								fmt.Println("[mint] can not share signature:", err)
 								continue
 							}
 
 							fmt.Println("[mint] gnosis safe tx signed: nonce", mintEntry.SafeTxNonce, "safeTxHash", mintEntry.SafeTxHash)
 
+							// sign burn tx
+							if token.IsLocked() {
+								txhash, err := a.RemoteTransaction(depositTokenAccount, hex.EncodeToString(burnTxHash[:]))
+								if err != nil {
+									fmt.Println("[mint] tx failed:", err)
+									continue
+								}
+								fmt.Println("[mint] tx sent:", txhash)
+							}
+
 							// sign data entry
 							txhash, err := a.RemoteTransaction(mintQueue, entryhash)
 							if err != nil {
@@ -1206,8 +2538,368 @@ func processNewDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gno
 
 }
 
+// watchCensorship makes auditors scan deposits and burns on their own and report valid ones,
+// which the leader has not queued within the censorship window
+func watchCensorship(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge string, safe string, window time.Duration, die chan bool) {
+
+	tracker := audit.NewTracker(window)
+
+	// the latest EVM block scanned without unqueued burns
+	var scannedHeight int64
+
+	for {
+
+		select {
+		default:
+
+			time.Sleep(time.Duration(1) * time.Minute)
+
+			// only auditors watch the leader, the report is reset when the node becomes leader
+			if !global.IsOnline || !global.IsAudit {
+				if tracker.Flagged > 0 {
+					tracker = audit.NewTracker(window)
+					publishCensorshipReport(tracker)
+				}
+				break
+			}
+
+			now := time.Now()
+
+			// counters of this scan, added to the node metrics
+			scan := &schema.Metrics{CensorshipScans: 1}
+
+			for _, token := range utils.GetTokens() {
+
+				mintQueue := accumulate.GenerateMintDataAccount(a.ADI, int64(e.ChainId), accumulate.ACC_MINT_QUEUE, token.Symbol)
+
+				events, err := findUnqueuedDeposits(a, e, token, mintQueue, window)
+				if err != nil {
+					fmt.Println("[censorship] Unable to check deposits of", token.Symbol, err)
+					scan.CensorshipScanErrors++
+					continue
+				}
+
+				for _, event := range tracker.Update(mintQueue, events, now) {
+					log.Warn("leader missed deposit ", event.ID, ": ", event.Reason)
+					scan.FlaggedDeposits++
+				}
+
+			}
+
+			releaseQueue := accumulate.GenerateReleaseDataAccount(a.ADI, int64(e.ChainId), accumulate.ACC_RELEASE_QUEUE)
+
+			events, err := findUnqueuedBurns(a, e, bridge, safe, releaseQueue, window, &scannedHeight)
+			if err != nil {
+				fmt.Println("[censorship] Unable to check burns:", err)
+				scan.CensorshipScanErrors++
+			} else {
+				for _, event := range tracker.Update(releaseQueue, events, now) {
+					log.Warn("leader missed burn ", event.ID, " log index ", event.LogIndex, ": ", event.Reason)
+					scan.FlaggedBurns++
+				}
+			}
+
+			scan.ScannedBurnHeight = scannedHeight
+
+			publishCensorshipReport(tracker)
+			addCensorshipMetrics(scan)
+
+		case <-die:
+			return
+		}
+
+	}
+
+}
+
+// publishCensorshipReport makes the latest report available to API
+func publishCensorshipReport(tracker *audit.Tracker) {
+
+	report := tracker.Report()
+
+	global.CensorshipLock.Lock()
+	global.Censorship = *report
+	global.CensorshipLock.Unlock()
+
+	if len(report.Items) > 0 {
+		fmt.Println("[censorship] Leader has not queued", report.MissedDeposits, "deposit(s) and", report.MissedBurns, "burn(s) within", tracker.Window)
+	}
+
+}
+
+// addCensorshipMetrics adds counters of the censorship scan to the node metrics
+func addCensorshipMetrics(scan *schema.Metrics) {
+
+	global.MetricsLock.Lock()
+	defer global.MetricsLock.Unlock()
+
+	global.Metrics.CensorshipScans += scan.CensorshipScans
+	global.Metrics.CensorshipScanErrors += scan.CensorshipScanErrors
+	global.Metrics.FlaggedDeposits += scan.FlaggedDeposits
+	global.Metrics.FlaggedBurns += scan.FlaggedBurns
+	global.Metrics.ScannedBurnHeight = scan.ScannedBurnHeight
+
+}
+
+// findUnqueuedDeposits returns valid deposits after the latest completed entry of the mint queue, which are not in pending entries.
+// Deposits are matched with entries by txid, the history of the token account also has txs of other types (e.g. burns of lock mode tokens)
+func findUnqueuedDeposits(a *accumulate.AccumulateClient, e *evm.EVMClient, token *schema.Token, mintQueue string, window time.Duration) ([]*schema.MissedEvent, error) {
+
+	// leader does not mint paused tokens, deposits are processed after unpause
+	if !token.IsLocked() {
+		paused, err := e.IsPaused(token.EVMAddress)
+		if err != nil {
+			return nil, err
+		}
+		if paused {
+			return nil, nil
+		}
+	}
+
+	latestMintEntry, err := a.QueryLatestDataEntry(&accumulate.Params{URL: mintQueue})
+	if err != nil {
+		return nil, err
+	}
+
+	latestMint, err := schema.ParseDepositEvent(latestMintEntry.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	queued := latestMint.SeqNumber
+
+	// txids of pending entries
+	queuedTxs := make(map[string]bool)
+
+	pending, err := a.QueryPendingChain(&accumulate.Params{URL: mintQueue})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entryhash := range pending.Items {
+
+		entry, err := a.QueryDataEntry(&accumulate.Params{URL: entryhash + "@" + mintQueue})
+		if err != nil {
+			return nil, err
+		}
+
+		mintEntry, err := schema.ParseDepositEvent(entry.Data)
+		if err != nil {
+			continue
+		}
+
+		queuedTxs[strings.ToLower(mintEntry.TxID)] = true
+
+	}
+
+	tokenAccount := accumulate.GenerateTokenAccount(a.ADI, int64(e.ChainId), token.Symbol)
+
+	// history is read around the seq number of the latest entry, deposits are checked after its tx
+	start := queued - NUMBER_OF_ACCUMULATE_TOKEN_TXS/2
+	if start < 0 {
+		start = 0
+	}
+
+	txs, err := a.QueryTxHistory(&accumulate.Params{URL: tokenAccount, Start: start, Count: NUMBER_OF_ACCUMULATE_TOKEN_TXS})
+	if err != nil {
+		return nil, err
+	}
+
+	first := int(queued-start) + 1
+
+	// entries written by set-mint-height have no txid, history is checked after their seq number
+	if latestMint.TxID != "" {
+		first = -1
+		for i, tx := range txs.Items {
+			if strings.EqualFold(tx.TxID, latestMint.TxID) {
+				first = i + 1
+				break
+			}
+		}
+		if first < 0 {
+			return nil, fmt.Errorf("tx %s of the latest mint entry is not found in %s from seq number %d", latestMint.TxID, tokenAccount, start)
+		}
+	}
+
+	if first > len(txs.Items) {
+		first = len(txs.Items)
+	}
+
+	events := []*schema.MissedEvent{}
+
+	for i, tx := range txs.Items[first:] {
+
+		if queuedTxs[strings.ToLower(tx.TxID)] {
+			continue
+		}
+
+		// same checks as the leader does, invalid deposits are not expected in the queue
+		if err := utils.ValidateDepositTx(tx); err != nil {
+			continue
+		}
+
+		cause, err := a.QueryTokenTx(&accumulate.Params{URL: tx.Data.Cause})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := utils.ValidateCauseTx(cause); err != nil {
+			continue
+		}
+
+		amount, ok := new(big.Int).SetString(tx.Data.Amount, 10)
+		if !ok {
+			continue
+		}
+
+		if err := validator.New().Var(cause.Transaction.Header.Memo, "required,eth_addr"); err != nil {
+			continue
+		}
+
+		operation := &fees.Operation{Token: token, Amount: amount.Int64()}
+		if _, err := operation.ApplyFees(&global.BridgeFees, fees.OP_MINT); err != nil {
+			continue
+		}
+
+		seqNumber := start + int64(first+i)
+
+		events = append(events, &schema.MissedEvent{
+			Type:        audit.EVENT_DEPOSIT,
+			Queue:       mintQueue,
+			ID:          tx.TxID,
+			SeqNumber:   seqNumber,
+			Token:       token.Symbol,
+			Amount:      amount.Int64(),
+			Destination: cause.Transaction.Header.Memo,
+			Reason:      fmt.Sprintf("valid deposit %s with seq number %d is not queued for %s, the latest completed seq number is %d, leader term %d", tx.TxID, seqNumber, window, queued, global.LeaderTerm),
+		})
+
+	}
+
+	return events, nil
+
+}
+
+// findUnqueuedBurns returns valid burn logs after the latest completed or pending entry of the release queue,
+// scannedHeight is the latest scanned block without unqueued burns, to avoid scanning the same blocks every time
+func findUnqueuedBurns(a *accumulate.AccumulateClient, e *evm.EVMClient, bridge string, safe string, releaseQueue string, window time.Duration, scannedHeight *int64) ([]*schema.MissedEvent, error) {
+
+	latestReleaseEntry, err := a.QueryLatestDataEntry(&accumulate.Params{URL: releaseQueue})
+	if err != nil {
+		return nil, err
+	}
+
+	queued, err := schema.ParseBurnEvent(latestReleaseEntry.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	pending, err := a.QueryPendingChain(&accumulate.Params{URL: releaseQueue})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entryhash := range pending.Items {
+
+		entry, err := a.QueryDataEntry(&accumulate.Params{URL: entryhash + "@" + releaseQueue})
+		if err != nil {
+			return nil, err
+		}
+
+		burnEntry, err := schema.ParseBurnEvent(entry.Data)
+		if err != nil {
+			continue
+		}
+
+		if queued.IsBefore(burnEntry.Position()) {
+			queued = burnEntry
+		}
+
+	}
+
+	start := queued.BlockHeight
+	if queued.BlockHash == "" {
+		start++
+	}
+	if *scannedHeight >= start {
+		start = *scannedHeight + 1
+	}
+
+	events := []*schema.MissedEvent{}
+
+	// scan blocks in chunks until the first chunk with unqueued burns, burns the leader skips do not stop the scan
+	err = e.ScanReleaseLogs(bridge, safe, utils.GetLockedTokens(), &evm.BlockRange{From: start}, func(scanned *evm.BlockRange, chunkLogs []*evm.EventLog) error {
+
+		for _, l := range chunkLogs {
+
+			if !queued.IsBefore(int64(l.BlockHeight), uint64(l.LogIndex)) {
+				continue
+			}
+
+			// burns of unknown tokens and burns below fees are skipped by the leader
+			token := utils.SearchEVMToken(l.Token.String())
+			if token == nil || token.IsLocked() != l.Locked {
+				continue
+			}
+
+			operation := &fees.Operation{Token: token, Amount: l.Amount.Int64()}
+			if _, err := operation.ApplyFees(&global.BridgeFees, fees.OP_RELEASE); err != nil {
+				continue
+			}
+
+			// same checks as the leader does, tx is cross-checked with other providers if quorum is configured
+			burnTx, err := e.GetTx(l.TxID.Hex())
+			if err != nil {
+				return err
+			}
+
+			if err := utils.ValidateBurnTx(burnTx, l); err != nil {
+				continue
+			}
+
+			// burns to invalid destinations are queued as exceptions
+			expected := "released"
+			err = utils.ValidateDestination(a, l.Destination, token.URL)
+			if err != nil && !errors.Is(err, utils.ErrInvalidDestination) {
+				return err
+			}
+			if err != nil {
+				expected = "recorded as exception"
+			}
+
+			events = append(events, &schema.MissedEvent{
+				Type:        audit.EVENT_BURN,
+				Queue:       releaseQueue,
+				ID:          l.TxID.Hex(),
+				BlockHeight: int64(l.BlockHeight),
+				LogIndex:    uint64(l.LogIndex),
+				Token:       token.Symbol,
+				Amount:      l.Amount.Int64(),
+				Destination: l.Destination,
+				Reason:      fmt.Sprintf("valid burn at height %d log index %d, which should be %s, is not queued for %s, the latest queued burn is at height %d log index %d, leader term %d", l.BlockHeight, l.LogIndex, expected, window, queued.BlockHeight, queued.LogIndex, global.LeaderTerm),
+			})
+
+		}
+
+		if len(events) > 0 {
+			return evm.ErrStopScan
+		}
+
+		*scannedHeight = scanned.To
+
+		return nil
+
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+
+}
+
 // submitEVMTxs
-func submitEVMTxs(e *evm.EVMClient, g *gnosis.Gnosis, die chan bool) {
+func submitEVMTxs(a *accumulate.AccumulateClient, e *evm.EVMClient, g *gnosis.Gnosis, txm *evm.TxManager, die chan bool) {
 
 	for {
 
@@ -1218,34 +2910,52 @@ func submitEVMTxs(e *evm.EVMClient, g *gnosis.Gnosis, die chan bool) {
 
 			if global.IsOnline {
 
-				if global.IsLeader {
+				// check receipts of submitted txs and speed up stuck ones
+				final, err := txm.Check()
+				if err != nil {
+					fmt.Println("[submit] can not check submitted txs:", err)
+				}
+
+				for _, tx := range final {
+					fmt.Println("[submit] safetxhash:", tx.ID, "tx:", tx.Hash().Hex(), "nonce:", tx.Nonce, "status:", tx.Status)
+				}
 
-					// get gnosis safe
-					safe, err := g.GetSafe()
+				if global.HoldsLease() {
+
+					// get gnosis safe state from the chain, cross-checked with gnosis safe api
+					safe, err := g.GetSafeState()
 					if err != nil {
 						fmt.Println("[submit] can not get gnosis safe:", err)
 						break
 					}
 
-					nonce, err := strconv.ParseInt(safe.Nonce, 10, 64)
-					if err != nil {
-						fmt.Println("[submit] can not parse int from nonce string:", err)
-						break
-					}
+					nonce := safe.Nonce
 
-					txs, err := g.GetSafeMultisigTxByNonce(nonce)
+					// signed txs from gnosis safe api and/or accumulate signatures data account
+					txs, err := utils.GetSignedSafeTxs(a, g, safe)
 					if err != nil {
 						fmt.Println("[submit] can not get gnosis safe txs:", err)
 						break
 					}
 
-					for _, tx := range txs.Results {
+					for _, tx := range txs {
+
+						if tx.Nonce != nonce {
+							continue
+						}
 
 						fmt.Println("[submit] found safetxhash:", tx.SafeTxHash, "nonce:", tx.Nonce)
 
 						// check if tx has been already submitted to the evm network
-						if global.LeaderLatestSubmittedTx == tx.SafeTxHash {
-							fmt.Println("[submit] tx is already submitted")
+						if txm.IsPending(tx.SafeTxHash) {
+							fmt.Println("[submit] tx is already submitted, waiting for receipt")
+							break
+						}
+
+						// tx is mined, but safe service is not updated yet
+						// reverted and replaced txs are submitted again
+						if submitted := txm.GetFinalized(tx.SafeTxHash); submitted != nil && submitted.Status == evm.TX_STATUS_SUCCESS {
+							fmt.Println("[submit] tx is already executed:", submitted.Hash().Hex())
 							break
 						}
 
@@ -1255,36 +2965,30 @@ func submitEVMTxs(e *evm.EVMClient, g *gnosis.Gnosis, die chan bool) {
 							break
 						}
 
-						// check number of signatures
-						if len(tx.Confirmations) < int(safe.Threshold) {
-							fmt.Println("[submit]", len(tx.Confirmations), "signatures,", safe.Threshold, "required")
-							break
+						// recover signers, invalid and non-owner signatures are dropped
+						sigs, err := g.VerifyConfirmations(safe, tx)
+						if err != nil {
+							fmt.Println("[submit] can not verify signatures:", err)
+							continue
 						}
 
-						// sort signatures
-						sort.Slice(tx.Confirmations, func(i, j int) bool {
-							switch strings.Compare(strings.ToLower(tx.Confirmations[i].Owner), strings.ToLower(tx.Confirmations[j].Owner)) {
-							case -1:
-								return true
-							case 1:
-								return false
-							}
-							return strings.ToLower(tx.Confirmations[i].Owner) > strings.ToLower(tx.Confirmations[j].Owner)
-						})
+						// check number of valid signatures, another tx with the same nonce may have enough of them
+						if len(sigs) < int(safe.Threshold) {
+							fmt.Println("[submit]", len(sigs), "valid signatures,", safe.Threshold, "required")
+							continue
+						}
 
-						// concatenate signatures
-						var sig []byte
-						for _, con := range tx.Confirmations {
-							sigBytes, err := hexutil.Decode(con.Signature)
-							if err != nil {
-								fmt.Println("[submit] can not decode signature hex:", err)
-								break
-							}
-							sig = append(sig, sigBytes...)
+						safeTx, err := g.SafeTxFromMultisigTx(tx)
+						if err != nil {
+							fmt.Println("[submit] invalid safe tx:", err)
+							continue
 						}
 
-						// generate tx input data
-						txData, err := abiutil.GenerateExecTransaction(g.BridgeAddress, tx.Data, hexutil.Encode(sig))
+						// signatures are ordered by owner address, only threshold of them is checked by the safe
+						sig := gnosis.EncodeSignatures(sigs[:safe.Threshold])
+
+						// generate tx input data, safe tx target is the bridge (mint), the token (unlock) or any admin target
+						txData, err := safeTx.EncodeExec(sig)
 						if err != nil {
 							fmt.Println("[submit] can not generate tx data:", err)
 							break
@@ -1292,16 +2996,33 @@ func submitEVMTxs(e *evm.EVMClient, g *gnosis.Gnosis, die chan bool) {
 
 						to := common.HexToAddress(g.SafeAddress)
 
-						// submit ethereum tx
-						sentTx, err := e.Submit(e.MaxGasFee, e.MaxPriorityFee, &to, 0, txData)
+						// simulate execTransaction at the latest block, txs that would fail are not broadcasted
+						// another tx with the same nonce may succeed (e.g. one with valid signatures)
+						out, err := e.Simulate(&to, 0, txData)
+						if err == nil {
+							err = gnosis.CheckExecResult(out)
+						}
+						var revert *evm.RevertError
+						if errors.As(err, &revert) || errors.Is(err, gnosis.ErrExecFailed) {
+							fmt.Println("[submit] safetxhash", tx.SafeTxHash, "would fail, skipping:", gnosis.DescribeRevert(err))
+							continue
+						}
+						if err != nil {
+							fmt.Println("[submit]", err)
+							break
+						}
+
+						// submit ethereum tx, tx manager prevents duplicate submission
+						sentTx, err := txm.Submit(tx.SafeTxHash, &to, 0, txData)
+						if errors.Is(err, evm.ErrRevertLimit) {
+							fmt.Println("[submit] safetxhash", tx.SafeTxHash, "is not submitted anymore, manual action required:", err)
+							break
+						}
 						if err != nil {
 							fmt.Println("[submit] ethereum tx error:", err)
 							break
 						}
 
-						// update latest submitted tx to prevent duplicate submission
-						global.LeaderLatestSubmittedTx = tx.SafeTxHash
-
 						fmt.Println("[submit] tx sent:", sentTx.Hash().Hex())
 
 					}
@@ -2,13 +2,23 @@ package schema
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/AccumulateNetwork/bridge/accumulate"
 )
 
+const (
+	TOKEN_MODE_MINT = "mint" // Accumulate-native token, locked on Accumulate, wrapped token is minted on EVM
+	TOKEN_MODE_LOCK = "lock" // EVM-native token, locked on EVM, issued on Accumulate by the bridge ADI
+)
+
 // BridgeFees schema
 type BridgeFees struct {
 	MintFee int64 `json:"mintFee" validate:"gte=0"`
@@ -19,8 +29,9 @@ type BridgeFees struct {
 type TokenEntry struct {
 	URL       string          `json:"url" validate:"required"`
 	Enabled   bool            `json:"enabled"`
-	Symbol    string          `json:"-"` // do not marshal symbol
-	Precision int64           `json:"-"` // do not marshal precision
+	Mode      string          `json:"mode,omitempty" validate:"omitempty,oneof=mint lock"` // empty is mint
+	Symbol    string          `json:"-"`                                                   // do not marshal symbol
+	Precision int64           `json:"-"`                                                   // do not marshal precision
 	Wrapped   []*WrappedToken `json:"wrapped" validate:"required"`
 }
 
@@ -45,17 +56,62 @@ type Token struct {
 	EVMSymbol     string  `json:"evmSymbol"`
 	EVMDecimals   int64   `json:"evmDecimals"`
 	EVMMintTxCost float64 `json:"evmMintTxCost" validate:"gte=0"`
+	Mode          string  `json:"mode"`
+}
+
+// IsLocked returns true if token is EVM-native and is locked on EVM instead of being burned
+func (t *Token) IsLocked() bool {
+	return t.Mode == TOKEN_MODE_LOCK
+}
+
+// TokenChange is a change of the token list, applied by the token registry watcher
+type TokenChange struct {
+	Time   time.Time `json:"time"`
+	Action string    `json:"action"` // added, updated, removed
+	URL    string    `json:"url"`
+	Token  *Token    `json:"token"`
 }
 
 // BurnEvent is an event of token burns on the EVM side
 type BurnEvent struct {
 	EVMTxID      string `json:"evmTxID"`
 	BlockHeight  int64  `json:"blockHeight"`
+	BlockHash    string `json:"blockHash,omitempty"` // empty in entries created before log index was recorded
+	LogIndex     uint64 `json:"logIndex"`
 	TokenAddress string `json:"tokenAddress"`
 	Amount       int64  `json:"amount"`
 	Destination  string `json:"destination"`
 	TokenURL     string `json:"-"`
 	TxHash       string `json:"txHash"`
+	Exception    string `json:"exception,omitempty"` // reason, if burn is not released (e.g. invalid destination)
+	Term         int64  `json:"term,omitempty"`      // leader term, entries of other terms are rejected by auditors
+}
+
+// Position returns block height and log index of burn event log
+// Entries without block hash were created before log index was recorded and cover the whole block
+func (b *BurnEvent) Position() (int64, uint64) {
+	if b.BlockHash == "" {
+		return b.BlockHeight, math.MaxUint64
+	}
+	return b.BlockHeight, b.LogIndex
+}
+
+// IsBefore checks if burn event log precedes the log at given block height and log index
+func (b *BurnEvent) IsBefore(height int64, logIndex uint64) bool {
+	bHeight, bLogIndex := b.Position()
+	if bHeight != height {
+		return bHeight < height
+	}
+	return bLogIndex < logIndex
+}
+
+// Previous returns position of the log right before the burn event log, so that scan from it starts with the burn
+// Entries without block hash cover the whole block, so the previous position is the end of the previous block
+func (b *BurnEvent) Previous() *BurnEvent {
+	if b.BlockHash == "" || b.LogIndex == 0 {
+		return &BurnEvent{BlockHeight: b.BlockHeight - 1}
+	}
+	return &BurnEvent{BlockHeight: b.BlockHeight, BlockHash: b.BlockHash, LogIndex: b.LogIndex - 1}
 }
 
 // DepositEvent is an event of token deposit into bridge token account
@@ -69,6 +125,72 @@ type DepositEvent struct {
 	TokenAddress string `json:"-"`
 	SafeTxHash   string `json:"safeTxHash"`
 	SafeTxNonce  int64  `json:"safeTxNonce"`
+	BurnTxHash   string `json:"burnTxHash,omitempty"` // burn of deposited tokens on Accumulate, for tokens in lock mode
+	Term         int64  `json:"term,omitempty"`       // leader term, entries of other terms are rejected by auditors
+}
+
+// SafeSignature is EIP-712 signature of gnosis safe tx by a bridge node, published to signatures data account
+type SafeSignature struct {
+	SafeTxHash  string `json:"safeTxHash"`
+	SafeTxNonce int64  `json:"safeTxNonce"`
+	To          string `json:"to"`
+	Data        string `json:"data"`
+	Owner       string `json:"owner"`
+	Signature   string `json:"signature"`
+	TxID        string `json:"txid"`           // deposit txid of the mint queue entry
+	Term        int64  `json:"term,omitempty"` // leader term of the mint queue entry
+}
+
+// Rejection is a vote of a bridge node against a pending queue entry, published to rejections data account
+type Rejection struct {
+	Queue      string `json:"queue"`
+	EntryHash  string `json:"entryHash"`            // pending tx hash of the entry
+	Node       string `json:"node"`                 // public key hash of the rejecting node
+	Reason     string `json:"reason"`               // validation error
+	SafeTxHash string `json:"safeTxHash,omitempty"` // safe tx of mint entry, recovered by the leader
+	BurnTxHash string `json:"burnTxHash,omitempty"` // burn tx of mint entry, recovered by the leader
+	PublicKey  string `json:"publicKey"`            // ed25519 public key of the node, hex
+	Signature  string `json:"signature"`            // ed25519 signature of RejectionHash, hex
+}
+
+// MissedEvent is a valid deposit or burn, which is not queued by the leader
+type MissedEvent struct {
+	Type        string    `json:"type"`  // deposit or burn
+	Queue       string    `json:"queue"` // mint or release queue the event is expected in
+	ID          string    `json:"id"`    // accumulate txid of deposit, evm txid of burn
+	SeqNumber   int64     `json:"seqNumber,omitempty"`
+	BlockHeight int64     `json:"blockHeight,omitempty"`
+	LogIndex    uint64    `json:"logIndex,omitempty"`
+	Token       string    `json:"token"`
+	Amount      int64     `json:"amount"`
+	Destination string    `json:"destination"`
+	FirstSeen   time.Time `json:"firstSeen"` // when the auditor found the event unqueued
+	Reason      string    `json:"reason"`
+}
+
+// CensorshipReport is the list of events missed by the leader, used by API
+type CensorshipReport struct {
+	Window         int64          `json:"window"` // minutes
+	MissedDeposits int64          `json:"missedDeposits"`
+	MissedBurns    int64          `json:"missedBurns"`
+	TotalFlagged   int64          `json:"totalFlagged"` // events flagged since the node started
+	Items          []*MissedEvent `json:"items"`
+}
+
+// Metrics are counters of the bridge node since it started, used by API
+type Metrics struct {
+	CensorshipScans      int64 `json:"censorshipScans"`      // auditor scans of deposits and burns
+	CensorshipScanErrors int64 `json:"censorshipScanErrors"` // failed scans of a mint or release queue
+	FlaggedDeposits      int64 `json:"flaggedDeposits"`      // deposits not queued by the leader within the censorship window
+	FlaggedBurns         int64 `json:"flaggedBurns"`         // burns not queued by the leader within the censorship window
+	ScannedBurnHeight    int64 `json:"scannedBurnHeight"`    // the latest EVM block scanned without unqueued burns
+}
+
+// Heartbeat is signed liveness message of a bridge node, published to heartbeat data account
+type Heartbeat struct {
+	PublicKey string `json:"publicKey"` // ed25519 public key of the node, hex
+	Timestamp int64  `json:"timestamp"` // unix time
+	Signature string `json:"signature"` // ed25519 signature of HeartbeatHash, hex
 }
 
 // ParseBurnEvent parses accumulate data entry into burn event and validates it
@@ -140,3 +262,217 @@ func ParseDepositEvent(entry *accumulate.DataEntry) (*DepositEvent, error) {
 	return mintEntry, nil
 
 }
+
+// ParseSafeSignature parses accumulate data entry into safe tx signature
+func ParseSafeSignature(entry *accumulate.DataEntry) (*SafeSignature, error) {
+
+	sig := &SafeSignature{}
+
+	// check version
+	if len(entry.Entry.Data) < 2 {
+		return nil, fmt.Errorf("looking for at least 2 data fields in entry, found %d", len(entry.Entry.Data))
+	}
+
+	version, err := hex.DecodeString(entry.Entry.Data[0])
+	if err != nil {
+		return nil, fmt.Errorf("can not decode entry data")
+	}
+
+	if !bytes.Equal(version, []byte(accumulate.SIGNATURES_VERSION)) {
+		return nil, fmt.Errorf("entry version is not %s", accumulate.SIGNATURES_VERSION)
+	}
+
+	// convert entry data to bytes
+	sigBytes, err := hex.DecodeString(entry.Entry.Data[1])
+	if err != nil {
+		return nil, fmt.Errorf("can not decode entry data")
+	}
+
+	// try to unmarshal the entry
+	err = json.Unmarshal(sigBytes, sig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal entry data")
+	}
+
+	return sig, nil
+
+}
+
+// HeartbeatHash is hash of bridge ADI, node public key and timestamp, signed by the node
+func HeartbeatHash(adi string, publicKey string, timestamp int64) []byte {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%d", adi, publicKey, timestamp)))
+	return hash[:]
+}
+
+// NewHeartbeat generates heartbeat of the node at the given time
+func NewHeartbeat(adi string, key ed25519.PrivateKey, timestamp time.Time) *Heartbeat {
+
+	h := &Heartbeat{}
+	h.PublicKey = hex.EncodeToString(key.Public().(ed25519.PublicKey))
+	h.Timestamp = timestamp.Unix()
+	h.Signature = hex.EncodeToString(ed25519.Sign(key, HeartbeatHash(adi, h.PublicKey, h.Timestamp)))
+
+	return h
+
+}
+
+// Verify checks heartbeat signature, returns public key hash of the node
+func (h *Heartbeat) Verify(adi string) (string, error) {
+
+	publicKey, err := hex.DecodeString(h.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return "", fmt.Errorf("invalid public key %s", h.PublicKey)
+	}
+
+	signature, err := hex.DecodeString(h.Signature)
+	if err != nil {
+		return "", fmt.Errorf("can not decode signature")
+	}
+
+	if !ed25519.Verify(publicKey, HeartbeatHash(adi, strings.ToLower(h.PublicKey), h.Timestamp), signature) {
+		return "", fmt.Errorf("invalid signature of %s", h.PublicKey)
+	}
+
+	publicKeyHash := sha256.Sum256(publicKey)
+
+	return hex.EncodeToString(publicKeyHash[:]), nil
+
+}
+
+// RejectionHash is hash of bridge ADI and the rejection without signature, signed by the rejecting node
+func RejectionHash(adi string, r *Rejection) ([]byte, error) {
+
+	unsigned := *r
+	unsigned.Signature = ""
+
+	rejectionBytes, err := json.Marshal(&unsigned)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(append([]byte(adi+":"), rejectionBytes...))
+
+	return hash[:], nil
+
+}
+
+// Sign signs the rejection with the node key, the node is set to public key hash of the key
+func (r *Rejection) Sign(adi string, key ed25519.PrivateKey) error {
+
+	publicKey := key.Public().(ed25519.PublicKey)
+	publicKeyHash := sha256.Sum256(publicKey)
+
+	r.PublicKey = hex.EncodeToString(publicKey)
+	r.Node = hex.EncodeToString(publicKeyHash[:])
+
+	hash, err := RejectionHash(adi, r)
+	if err != nil {
+		return err
+	}
+
+	r.Signature = hex.EncodeToString(ed25519.Sign(key, hash))
+
+	return nil
+
+}
+
+// Verify checks rejection signature and that it is signed by the rejecting node
+func (r *Rejection) Verify(adi string) error {
+
+	publicKey, err := hex.DecodeString(r.PublicKey)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key %s", r.PublicKey)
+	}
+
+	publicKeyHash := sha256.Sum256(publicKey)
+	if !strings.EqualFold(hex.EncodeToString(publicKeyHash[:]), r.Node) {
+		return fmt.Errorf("rejection of %s is signed by another key %s", r.Node, r.PublicKey)
+	}
+
+	signature, err := hex.DecodeString(r.Signature)
+	if err != nil {
+		return fmt.Errorf("can not decode signature")
+	}
+
+	hash, err := RejectionHash(adi, r)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(publicKey, hash, signature) {
+		return fmt.Errorf("invalid signature of %s", r.PublicKey)
+	}
+
+	return nil
+
+}
+
+// ParseHeartbeat parses accumulate data entry into heartbeat
+func ParseHeartbeat(entry *accumulate.DataEntry) (*Heartbeat, error) {
+
+	h := &Heartbeat{}
+
+	// check version
+	if len(entry.Entry.Data) < 2 {
+		return nil, fmt.Errorf("looking for at least 2 data fields in entry, found %d", len(entry.Entry.Data))
+	}
+
+	version, err := hex.DecodeString(entry.Entry.Data[0])
+	if err != nil {
+		return nil, fmt.Errorf("can not decode entry data")
+	}
+
+	if !bytes.Equal(version, []byte(accumulate.HEARTBEAT_VERSION)) {
+		return nil, fmt.Errorf("entry version is not %s", accumulate.HEARTBEAT_VERSION)
+	}
+
+	// convert entry data to bytes
+	heartbeatBytes, err := hex.DecodeString(entry.Entry.Data[1])
+	if err != nil {
+		return nil, fmt.Errorf("can not decode entry data")
+	}
+
+	// try to unmarshal the entry
+	err = json.Unmarshal(heartbeatBytes, h)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal entry data")
+	}
+
+	return h, nil
+
+}
+
+// ParseRejection parses accumulate data entry into rejection
+func ParseRejection(entry *accumulate.DataEntry) (*Rejection, error) {
+
+	rejection := &Rejection{}
+
+	// check version
+	if len(entry.Entry.Data) < 2 {
+		return nil, fmt.Errorf("looking for at least 2 data fields in entry, found %d", len(entry.Entry.Data))
+	}
+
+	version, err := hex.DecodeString(entry.Entry.Data[0])
+	if err != nil {
+		return nil, fmt.Errorf("can not decode entry data")
+	}
+
+	if !bytes.Equal(version, []byte(accumulate.REJECTIONS_VERSION)) {
+		return nil, fmt.Errorf("entry version is not %s", accumulate.REJECTIONS_VERSION)
+	}
+
+	// convert entry data to bytes
+	rejectionBytes, err := hex.DecodeString(entry.Entry.Data[1])
+	if err != nil {
+		return nil, fmt.Errorf("can not decode entry data")
+	}
+
+	// try to unmarshal the entry
+	err = json.Unmarshal(rejectionBytes, rejection)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal entry data")
+	}
+
+	return rejection, nil
+
+}
@@ -18,6 +18,15 @@ var ErrMissingHash = errors.New("missing hash")
 // ErrInvalidHash means that a transaction ID did not include a valid hash.
 var ErrInvalidHash = errors.New("invalid hash")
 
+// ErrNotLite means that a URL is not a lite account URL.
+var ErrNotLite = errors.New("not a lite account")
+
+// ErrInvalidChecksum means that a lite account URL has an invalid checksum.
+var ErrInvalidChecksum = errors.New("invalid checksum")
+
+// ErrMissingToken means that a lite token account URL did not include a token.
+var ErrMissingToken = errors.New("missing token")
+
 func missingHost(url string) error {
 	return fmt.Errorf("%w in URL %q", ErrMissingHost, url)
 }
@@ -33,3 +42,15 @@ func missingHash(url *URL) error {
 func invalidHash(url *URL, err interface{}) error {
 	return fmt.Errorf("%q is not a transaction ID: %w: %v", url, ErrInvalidHash, err)
 }
+
+func notLite(url *URL) error {
+	return fmt.Errorf("%q is %w", url, ErrNotLite)
+}
+
+func invalidChecksum(url *URL) error {
+	return fmt.Errorf("%w in lite account URL %q", ErrInvalidChecksum, url)
+}
+
+func missingToken(url *URL) error {
+	return fmt.Errorf("%w in lite token account URL %q", ErrMissingToken, url)
+}
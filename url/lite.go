@@ -0,0 +1,67 @@
+package url
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// liteKeyHashLength is the length of the key hash in the lite account authority.
+const liteKeyHashLength = 20
+
+// liteChecksumLength is the length of the checksum in the lite account
+// authority.
+const liteChecksumLength = 4
+
+// IsLite returns true if the URL authority looks like a lite account
+// authority: a hex-encoded key hash followed by a hex-encoded checksum. The
+// checksum is not verified.
+func (u *URL) IsLite() bool {
+	host := u.Hostname()
+	if len(host) != 2*(liteKeyHashLength+liteChecksumLength) {
+		return false
+	}
+	_, err := hex.DecodeString(host)
+	return err == nil
+}
+
+// ParseLiteTokenAddress parses the URL as a lite token account and returns the
+// key hash and the token URL. The authority checksum is verified and the path
+// must contain the token URL, e.g. `acc://<key hash><checksum>/ACME`.
+func ParseLiteTokenAddress(u *URL) ([]byte, *URL, error) {
+	if !u.IsLite() {
+		return nil, nil, notLite(u)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	keyStr, checkStr := host[:2*liteKeyHashLength], host[2*liteKeyHashLength:]
+
+	keyHash, err := hex.DecodeString(keyStr)
+	if err != nil {
+		return nil, nil, notLite(u)
+	}
+
+	checksum, err := hex.DecodeString(checkStr)
+	if err != nil {
+		return nil, nil, notLite(u)
+	}
+
+	expected := sha256.Sum256([]byte(keyStr))
+	if !bytes.Equal(checksum, expected[len(expected)-liteChecksumLength:]) {
+		return nil, nil, invalidChecksum(u)
+	}
+
+	tokenPath := strings.Trim(u.Path, "/")
+	if tokenPath == "" {
+		return nil, nil, missingToken(u)
+	}
+
+	token, err := Parse(tokenPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", missingToken(u), err)
+	}
+
+	return keyHash, token, nil
+}
@@ -102,3 +102,32 @@ func TestURL_ResourceChain(t *testing.T) {
 		})
 	}
 }
+
+func TestParseLiteTokenAddress(t *testing.T) {
+	cases := []struct {
+		url    string
+		token  string
+		expect error
+	}{
+		{"acc://abdafe3eb60d205905e10e5a2129e9567292646b968ecb7b/ACME", "acc://ACME", nil},
+		{"acc://ABDAFE3EB60D205905E10E5A2129E9567292646B968ECB7B/foo.acme/tokens", "acc://foo.acme/tokens", nil},
+		{"acc://abdafe3eb60d205905e10e5a2129e9567292646b968ecb7c/ACME", "", ErrInvalidChecksum},
+		{"acc://abdafe3eb60d205905e10e5a2129e9567292646b968ecb7b", "", ErrMissingToken},
+		{"acc://foo.acme/ACME", "", ErrNotLite},
+	}
+
+	for _, c := range cases {
+		t.Run("", func(t *testing.T) {
+			u, err := Parse(c.url)
+			require.NoError(t, err)
+			keyHash, token, err := ParseLiteTokenAddress(u)
+			if c.expect != nil {
+				require.ErrorIs(t, err, c.expect)
+			} else {
+				require.NoError(t, err)
+				require.Len(t, keyHash, 20)
+				require.Equal(t, c.token, token.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AccumulateNetwork/bridge/accumulate"
+	"github.com/AccumulateNetwork/bridge/evm"
+	"github.com/AccumulateNetwork/bridge/schema"
+)
+
+const NUMBER_OF_RELEASE_ENTRIES = 100
+
+// ErrNotRefundable means that lock transfer was released, or is not a lock transfer
+var ErrNotRefundable = errors.New("lock transfer is not refundable")
+
+// ErrReleaseEntryNotFound means that lock transfer is not in the release queue yet
+var ErrReleaseEntryNotFound = errors.New("release entry not found")
+
+// FindReleaseEntry finds completed release queue entry of the lock transfer.
+// Entries are written in order of EVM logs, so the release queue is read from the latest entry back to the lock position
+func FindReleaseEntry(a *accumulate.AccumulateClient, releaseQueue string, lock *evm.EventLog) (*schema.BurnEvent, error) {
+
+	// get total number of entries to read the latest ones
+	dataSet, err := a.QueryDataSet(&accumulate.Params{URL: releaseQueue, Count: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	for end := dataSet.Total; end > 0; end -= NUMBER_OF_RELEASE_ENTRIES {
+
+		start := end - NUMBER_OF_RELEASE_ENTRIES
+		if start < 0 {
+			start = 0
+		}
+
+		page, err := a.QueryDataSet(&accumulate.Params{URL: releaseQueue, Start: start, Count: end - start, Expand: true})
+		if err != nil {
+			return nil, err
+		}
+
+		for i := len(page.Items) - 1; i >= 0; i-- {
+
+			entry, err := schema.ParseBurnEvent(page.Items[i])
+			if err != nil {
+				fmt.Println("[refund] can not parse release entry", page.Items[i].EntryHash, err)
+				continue
+			}
+
+			if strings.EqualFold(entry.EVMTxID, lock.TxID.Hex()) && entry.BlockHash != "" && entry.LogIndex == uint64(lock.LogIndex) {
+				return entry, nil
+			}
+
+			if entry.IsBefore(int64(lock.BlockHeight), uint64(lock.LogIndex)) {
+				return nil, ErrReleaseEntryNotFound
+			}
+
+		}
+
+	}
+
+	return nil, ErrReleaseEntryNotFound
+
+}
+
+// ValidateRefund checks that lock transfer was recorded in the release queue as exception, so the tokens were not issued
+func ValidateRefund(entry *schema.BurnEvent, lock *evm.EventLog) error {
+
+	if !lock.Locked {
+		return fmt.Errorf("%w: tx %s log %d is not a lock transfer", ErrNotRefundable, lock.TxID.Hex(), lock.LogIndex)
+	}
+
+	if entry.Exception == "" {
+		return fmt.Errorf("%w: tx %s log %d was released by accumulate tx %s", ErrNotRefundable, lock.TxID.Hex(), lock.LogIndex, entry.TxHash)
+	}
+
+	if !strings.EqualFold(entry.EVMTxID, lock.TxID.Hex()) {
+		return fmt.Errorf("%w: entry txid=%s, lock txid=%s", ErrNotRefundable, entry.EVMTxID, lock.TxID.Hex())
+	}
+
+	err := ValidateBurnEntry(entry, lock)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotRefundable, err)
+	}
+
+	return nil
+
+}
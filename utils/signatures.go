@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/AccumulateNetwork/bridge/accumulate"
+	"github.com/AccumulateNetwork/bridge/gnosis"
+	"github.com/AccumulateNetwork/bridge/schema"
+)
+
+const NUMBER_OF_SIGNATURE_ENTRIES = 100
+
+// ShareSignature submits signed safe tx to gnosis safe API and/or publishes it to Accumulate signatures data account,
+// term is the leader term of the mint entry
+func ShareSignature(a *accumulate.AccumulateClient, g *gnosis.Gnosis, safe *gnosis.SafeState, safeTx *gnosis.NewMultisigTx, txid string, term int64) error {
+
+	// the term is carried by the safe api proposal as its origin
+	safeTx.Origin = gnosis.NewSafeTxOrigin(term)
+
+	if g.UsesSafeAPI() {
+		// duplicate means this signature was already submitted
+		err := g.CreateSafeMultisigTx(safeTx)
+		if err != nil && !errors.Is(err, gnosis.ErrDuplicateTx) {
+			return fmt.Errorf("gnosis safe api error: %s", err)
+		}
+	}
+
+	if !g.UsesAccumulate() {
+		return nil
+	}
+
+	// do not publish the same signature twice
+	signed, err := GetAccumulateSignedTxs(a, g, safe)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range signed {
+		if strings.EqualFold(tx.SafeTxHash, safeTx.ContractTransactionHash) && tx.HasConfirmation(g.PublicKey) {
+			return nil
+		}
+	}
+
+	sig := &schema.SafeSignature{
+		SafeTxHash:  safeTx.ContractTransactionHash,
+		SafeTxNonce: safeTx.Nonce,
+		To:          safeTx.To,
+		Data:        safeTx.Data,
+		Owner:       g.PublicKey.Hex(),
+		Signature:   safeTx.Signature,
+		TxID:        txid,
+		Term:        term,
+	}
+
+	sigBytes, err := json.Marshal(sig)
+	if err != nil {
+		return err
+	}
+
+	var content [][]byte
+	content = append(content, []byte(accumulate.SIGNATURES_VERSION))
+	content = append(content, sigBytes)
+
+	sigsAccount := accumulate.GenerateReleaseDataAccount(a.ADI, int64(g.ChainId), accumulate.ACC_SIGNATURES)
+
+	txhash, err := a.WriteDataAs(sigsAccount, a.SigsSigner, content)
+	if err != nil {
+		return fmt.Errorf("can not publish signature to %s: %s", sigsAccount, err)
+	}
+
+	fmt.Println("[sigs] signature published:", txhash)
+
+	return nil
+
+}
+
+// GetSignedSafeTxs gathers not executed safe txs with nonce >= safe nonce and their signatures from all signature transports
+func GetSignedSafeTxs(a *accumulate.AccumulateClient, g *gnosis.Gnosis, safe *gnosis.SafeState) ([]*gnosis.MultisigTx, error) {
+
+	var lists [][]*gnosis.MultisigTx
+
+	if g.UsesSafeAPI() {
+		executed := false
+		txs, err := g.QuerySafeMultisigTxs(&gnosis.MultisigTxsFilter{NonceFrom: &safe.Nonce, Executed: &executed})
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, txs.Results)
+	}
+
+	if g.UsesAccumulate() {
+		txs, err := GetAccumulateSignedTxs(a, g, safe)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, txs)
+	}
+
+	return gnosis.MergeMultisigTxs(lists...), nil
+
+}
+
+// GetAccumulateSignedTxs reads safe tx signatures with nonce >= safe nonce from Accumulate signatures data account,
+// both from the latest entries and from the pending chain, signatures not made by safe owners are skipped
+func GetAccumulateSignedTxs(a *accumulate.AccumulateClient, g *gnosis.Gnosis, safe *gnosis.SafeState) ([]*gnosis.MultisigTx, error) {
+
+	sigsAccount := accumulate.GenerateReleaseDataAccount(a.ADI, int64(g.ChainId), accumulate.ACC_SIGNATURES)
+
+	// get total number of entries to read the latest ones
+	dataSet, err := a.QueryDataSet(&accumulate.Params{URL: sigsAccount, Count: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	start := dataSet.Total - NUMBER_OF_SIGNATURE_ENTRIES
+	if start < 0 {
+		start = 0
+	}
+
+	dataSet, err = a.QueryDataSet(&accumulate.Params{URL: sigsAccount, Start: start, Count: NUMBER_OF_SIGNATURE_ENTRIES, Expand: true})
+	if err != nil {
+		return nil, err
+	}
+
+	entries := dataSet.Items
+
+	// signatures are pending, if signatures key page threshold is more than 1
+	pending, err := a.QueryPendingChain(&accumulate.Params{URL: sigsAccount})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entryhash := range pending.Items {
+		entry, err := a.QueryDataEntry(&accumulate.Params{URL: accumulate.GenerateDataEntry(sigsAccount, entryhash)})
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry.Data)
+	}
+
+	var txs []*gnosis.MultisigTx
+
+	for _, entry := range entries {
+
+		sig, err := schema.ParseSafeSignature(entry)
+		if err != nil {
+			fmt.Println("[sigs] can not parse signature entry", entry.EntryHash, err)
+			continue
+		}
+
+		if sig.SafeTxNonce < safe.Nonce {
+			continue
+		}
+
+		tx, err := g.VerifySignature(safe, sig.To, sig.Data, sig.SafeTxNonce, sig.SafeTxHash, sig.Signature)
+		if err != nil {
+			fmt.Println("[sigs] invalid signature of", sig.Owner, "for safetxhash", sig.SafeTxHash, err)
+			continue
+		}
+
+		tx.Origin = gnosis.NewSafeTxOrigin(sig.Term)
+
+		txs = append(txs, tx)
+
+	}
+
+	return txs, nil
+
+}
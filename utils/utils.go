@@ -1,23 +1,37 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/AccumulateNetwork/bridge/accumulate"
 	"github.com/AccumulateNetwork/bridge/evm"
 	"github.com/AccumulateNetwork/bridge/fees"
 	"github.com/AccumulateNetwork/bridge/global"
 	"github.com/AccumulateNetwork/bridge/schema"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/gommon/log"
 
 	acmeurl "github.com/AccumulateNetwork/bridge/url"
 )
 
+const TOKEN_CHANGES_LIMIT = 100
+
+const (
+	TOKEN_ADDED   = "added"
+	TOKEN_UPDATED = "updated"
+	TOKEN_REMOVED = "removed"
+)
+
 func SearchEVMToken(address string) *schema.Token {
 
+	global.TokensLock.RLock()
+	defer global.TokensLock.RUnlock()
+
 	for _, t := range global.Tokens.Items {
 		if strings.EqualFold(t.EVMAddress, address) {
 			return t
@@ -30,6 +44,9 @@ func SearchEVMToken(address string) *schema.Token {
 
 func SearchAccumulateToken(url string) *schema.Token {
 
+	global.TokensLock.RLock()
+	defer global.TokensLock.RUnlock()
+
 	for _, t := range global.Tokens.Items {
 		if strings.EqualFold(t.URL, url) {
 			return t
@@ -40,28 +57,189 @@ func SearchAccumulateToken(url string) *schema.Token {
 
 }
 
-func ValidateBurnEntry(entry *schema.BurnEvent, l *evm.EventLog) error {
+// GetTokens returns a snapshot of the current token list
+func GetTokens() []*schema.Token {
 
-	log.Debug("Validating burn entry")
+	global.TokensLock.RLock()
+	defer global.TokensLock.RUnlock()
 
-	log.Debug("entry amount=", entry.Amount, ", event log amount=", l.Amount)
-	if entry.Amount != l.Amount.Int64() {
-		return fmt.Errorf("entry amount=%d, event log amount=%d", entry.Amount, l.Amount)
+	tokens := make([]*schema.Token, len(global.Tokens.Items))
+	copy(tokens, global.Tokens.Items)
+
+	return tokens
+
+}
+
+// GetLockedTokens returns EVM addresses of tokens in lock mode, which are locked by transfer to the safe
+func GetLockedTokens() []string {
+
+	global.TokensLock.RLock()
+	defer global.TokensLock.RUnlock()
+
+	var addresses []string
+	for _, t := range global.Tokens.Items {
+		if t.IsLocked() {
+			addresses = append(addresses, t.EVMAddress)
+		}
+	}
+
+	return addresses
+
+}
+
+// DiffTokens compares two token lists and returns added, updated and removed tokens
+func DiffTokens(current []*schema.Token, next []*schema.Token) []*schema.TokenChange {
+
+	var changes []*schema.TokenChange
+	now := time.Now()
+
+	for _, n := range next {
+		found := false
+		for _, c := range current {
+			if strings.EqualFold(c.URL, n.URL) {
+				found = true
+				if *c != *n {
+					changes = append(changes, &schema.TokenChange{Time: now, Action: TOKEN_UPDATED, URL: n.URL, Token: n})
+				}
+				break
+			}
+		}
+		if !found {
+			changes = append(changes, &schema.TokenChange{Time: now, Action: TOKEN_ADDED, URL: n.URL, Token: n})
+		}
+	}
+
+	for _, c := range current {
+		found := false
+		for _, n := range next {
+			if strings.EqualFold(c.URL, n.URL) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			changes = append(changes, &schema.TokenChange{Time: now, Action: TOKEN_REMOVED, URL: c.URL, Token: c})
+		}
+	}
+
+	return changes
+
+}
+
+// ReplaceTokens atomically replaces the token list and records the changes
+func ReplaceTokens(tokens []*schema.Token) []*schema.TokenChange {
+
+	global.TokensLock.Lock()
+	defer global.TokensLock.Unlock()
+
+	changes := DiffTokens(global.Tokens.Items, tokens)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	global.Tokens.Items = tokens
+
+	global.TokenChanges = append(global.TokenChanges, changes...)
+	if len(global.TokenChanges) > TOKEN_CHANGES_LIMIT {
+		global.TokenChanges = global.TokenChanges[len(global.TokenChanges)-TOKEN_CHANGES_LIMIT:]
 	}
 
-	entryDestination, err := acmeurl.Parse(entry.Destination)
+	return changes
+
+}
+
+// ErrInvalidDestination means that release destination can never receive the token
+var ErrInvalidDestination = errors.New("invalid destination")
+
+// ValidateDestination checks if release destination can receive the token:
+// lite token accounts must have valid checksum and token suffix, ADI token accounts must exist and hold the token
+// errors, that are not wrapping ErrInvalidDestination, are temporary (e.g. api errors)
+func ValidateDestination(a *accumulate.AccumulateClient, destination string, tokenURL string) error {
+
+	destinationURL, err := acmeurl.Parse(destination)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidDestination, err)
+	}
+
+	token, err := acmeurl.Parse(tokenURL)
 	if err != nil {
 		return err
 	}
 
-	logDestination, err := acmeurl.Parse(l.Destination)
+	// lite token account is created on the first deposit, so only the address is validated
+	if destinationURL.IsLite() {
+		_, liteToken, err := acmeurl.ParseLiteTokenAddress(destinationURL)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidDestination, err)
+		}
+		if !liteToken.Equal(token) {
+			return fmt.Errorf("%w: lite token account %s holds %s, expected %s", ErrInvalidDestination, destination, liteToken, tokenURL)
+		}
+		return nil
+	}
+
+	account, err := a.QueryTokenAccount(&accumulate.Params{URL: destination})
 	if err != nil {
+		if accumulate.IsNotFound(err) {
+			return fmt.Errorf("%w: token account %s not found", ErrInvalidDestination, destination)
+		}
+		if errors.Is(err, accumulate.ErrNotTokenAccount) {
+			return fmt.Errorf("%w: %s", ErrInvalidDestination, err)
+		}
 		return err
 	}
 
-	log.Debug("entry destination=", entryDestination, ", event log destination=", logDestination)
-	if entryDestination.Authority != logDestination.Authority || entryDestination.Path != logDestination.Path {
-		return fmt.Errorf("entry destination=%s, event log destination=%s", entry.Destination, l.Destination)
+	accountToken, err := acmeurl.Parse(account.Data.TokenURL)
+	if err != nil {
+		return fmt.Errorf("%w: token account %s holds invalid token %q: %s", ErrInvalidDestination, destination, account.Data.TokenURL, err)
+	}
+
+	if !accountToken.Equal(token) {
+		return fmt.Errorf("%w: token account %s holds %s, expected %s", ErrInvalidDestination, destination, account.Data.TokenURL, tokenURL)
+	}
+
+	return nil
+
+}
+
+func ValidateBurnEntry(entry *schema.BurnEvent, l *evm.EventLog) error {
+
+	log.Debug("Validating burn entry")
+
+	log.Debug("entry amount=", entry.Amount, ", event log amount=", l.Amount)
+	if entry.Amount != l.Amount.Int64() {
+		return fmt.Errorf("entry amount=%d, event log amount=%d", entry.Amount, l.Amount)
+	}
+
+	log.Debug("entry destination=", entry.Destination, ", event log destination=", l.Destination)
+
+	// exception entries may have destinations, that are not valid urls, so compare raw strings first
+	if entry.Destination != l.Destination {
+
+		entryDestination, err := acmeurl.Parse(entry.Destination)
+		if err != nil {
+			return err
+		}
+
+		logDestination, err := acmeurl.Parse(l.Destination)
+		if err != nil {
+			return err
+		}
+
+		if entryDestination.Authority != logDestination.Authority || entryDestination.Path != logDestination.Path {
+			return fmt.Errorf("entry destination=%s, event log destination=%s", entry.Destination, l.Destination)
+		}
+
+	}
+
+	// entries created before log index was recorded are matched by txid only
+	if entry.BlockHash != "" {
+
+		log.Debug("entry log=", entry.BlockHash, ":", entry.LogIndex, ", event log=", l.BlockHash.Hex(), ":", l.LogIndex)
+		if !strings.EqualFold(entry.BlockHash, l.BlockHash.Hex()) || entry.LogIndex != uint64(l.LogIndex) {
+			return fmt.Errorf("entry log=%s:%d, event log=%s:%d", entry.BlockHash, entry.LogIndex, l.BlockHash.Hex(), l.LogIndex)
+		}
+
 	}
 
 	log.Debug("entry token=", entry.TokenAddress, ", event log token=", l.Token.Hex())
@@ -74,7 +252,48 @@ func ValidateBurnEntry(entry *schema.BurnEvent, l *evm.EventLog) error {
 
 }
 
-func ValidateReleaseTx(releaseTx *accumulate.TokenTx, l *evm.EventLog) error {
+// FindBurnLog finds event log of burn entry by txid and log index, returns nil if not found
+func FindBurnLog(logs []*evm.EventLog, entry *schema.BurnEvent) *evm.EventLog {
+
+	for _, l := range logs {
+
+		if !strings.EqualFold(l.TxID.Hex(), entry.EVMTxID) {
+			continue
+		}
+
+		// entries created before log index was recorded are matched by txid only
+		if entry.BlockHash == "" || uint64(l.LogIndex) == entry.LogIndex {
+			return l
+		}
+
+	}
+
+	return nil
+
+}
+
+// ValidateBurnTx checks that burn tx succeeded and is included in the block of burn event log
+func ValidateBurnTx(tx *evm.Tx, l *evm.EventLog) error {
+
+	if !strings.EqualFold(tx.TxHash, l.TxID.Hex()) {
+		return fmt.Errorf("tx=%s, event log tx=%s", tx.TxHash, l.TxID.Hex())
+	}
+
+	if tx.Status != types.ReceiptStatusSuccessful {
+		return fmt.Errorf("tx %s failed, receipt status=%d", tx.TxHash, tx.Status)
+	}
+
+	if !strings.EqualFold(tx.BlockHash, l.BlockHash.Hex()) {
+		return fmt.Errorf("tx block=%s, event log block=%s", tx.BlockHash, l.BlockHash.Hex())
+	}
+
+	return nil
+
+}
+
+func ValidateReleaseTx(tx *accumulate.QueryTokenTxResponse, l *evm.EventLog, chainId int64) error {
+
+	releaseTx := tx.Data
 
 	// find token
 	token := SearchEVMToken(l.Token.String())
@@ -83,6 +302,16 @@ func ValidateReleaseTx(releaseTx *accumulate.TokenTx, l *evm.EventLog) error {
 		return fmt.Errorf("token address %s is not supported by bridge", l.Token.String())
 	}
 
+	// EVM-native tokens are issued by the bridge, others are sent from the bridge token account
+	txType := accumulate.TX_TYPE_SEND_TOKENS
+	if token.IsLocked() {
+		txType = accumulate.TX_TYPE_ISSUE_TOKENS
+	}
+
+	if tx.Type != txType {
+		return fmt.Errorf("expected release tx type %s, got %s", txType, tx.Type)
+	}
+
 	operation := &fees.Operation{
 		Token:  token,
 		Amount: l.Amount.Int64(),
@@ -122,6 +351,23 @@ func ValidateReleaseTx(releaseTx *accumulate.TokenTx, l *evm.EventLog) error {
 		return fmt.Errorf("entry destination=%s, event log tx destination=%s", releaseTx.To[0].URL, l.Destination)
 	}
 
+	// release txs sent before the burn reference was added have no memo, they are paired with the burn by amount and destination only
+	if tx.Transaction.Header.Memo == "" && tx.Type == accumulate.TX_TYPE_SEND_TOKENS {
+		log.Debug("release tx without memo, validated by amount and destination")
+		return nil
+	}
+
+	// validate reference to the burn, so the same burn can not be paired with a different release
+	memoChainId, memoTxID, memoLogIndex, err := accumulate.ParseReleaseMemo(tx.Transaction.Header.Memo)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("release tx memo=", tx.Transaction.Header.Memo, ", event log chainId=", chainId, ", txid=", l.TxID.Hex(), ", log index=", l.LogIndex)
+	if memoChainId != chainId || !strings.EqualFold(memoTxID, l.TxID.Hex()) || memoLogIndex != uint64(l.LogIndex) {
+		return fmt.Errorf("release tx memo=%s, event log chainId=%d, txid=%s, log index=%d", tx.Transaction.Header.Memo, chainId, l.TxID.Hex(), l.LogIndex)
+	}
+
 	return nil
 
 }
@@ -158,6 +404,33 @@ func ValidateCauseTx(causeTx *accumulate.QueryTokenTxResponse) error {
 
 }
 
+// ValidateDepositBurnTx validates burn of deposited tokens in lock mode against mint entry
+func ValidateDepositBurnTx(tx *accumulate.QueryTokenTxResponse, entry *schema.DepositEvent, amount int64) error {
+
+	if tx.Type != accumulate.TX_TYPE_BURN_TOKENS {
+		return fmt.Errorf("expected tx type %s, got %s", accumulate.TX_TYPE_BURN_TOKENS, tx.Type)
+	}
+
+	burnAmount, err := strconv.ParseInt(tx.Data.Amount, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	log.Debug("burn tx amount=", burnAmount, ", mint amount=", amount)
+	if burnAmount != amount {
+		return fmt.Errorf("burn tx amount=%d, mint amount=%d", burnAmount, amount)
+	}
+
+	// burn references the deposit, so the same burn can not be paired with a different deposit
+	log.Debug("burn tx memo=", tx.Transaction.Header.Memo, ", entry txid=", entry.TxID)
+	if !strings.EqualFold(tx.Transaction.Header.Memo, entry.TxID) {
+		return fmt.Errorf("burn tx memo=%s, entry txid=%s", tx.Transaction.Header.Memo, entry.TxID)
+	}
+
+	return nil
+
+}
+
 func ValidateMintEntry(entry *schema.DepositEvent, tx *accumulate.QueryTokenTxResponse, cause *accumulate.QueryTokenTxResponse) error {
 
 	log.Debug("Validating mint entry")
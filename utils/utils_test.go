@@ -1 +1,311 @@
 package utils
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/AccumulateNetwork/bridge/accumulate"
+	"github.com/AccumulateNetwork/bridge/evm"
+	"github.com/AccumulateNetwork/bridge/global"
+	"github.com/AccumulateNetwork/bridge/schema"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/ybbus/jsonrpc/v3"
+)
+
+func TestDiffTokens(t *testing.T) {
+
+	acme := &schema.Token{URL: "acc://ACME", Symbol: "ACME", EVMAddress: "0x4E780D102AADECF1BdC06d91542cf91960538a2D"}
+	acmeUpdated := &schema.Token{URL: "acc://acme", Symbol: "ACME", EVMAddress: "0xC6386B0A95b60bCEa480C876e3b1F9AdB5B85314"}
+	foo := &schema.Token{URL: "acc://foo.acme/token", Symbol: "FOO"}
+	bar := &schema.Token{URL: "acc://bar.acme/token", Symbol: "BAR"}
+
+	// TEST 1: no changes
+	changes := DiffTokens([]*schema.Token{acme, foo}, []*schema.Token{acme, foo})
+	assert.Empty(t, changes)
+
+	// TEST 2: added, updated and removed
+	changes = DiffTokens([]*schema.Token{acme, foo}, []*schema.Token{acmeUpdated, bar})
+	assert.Len(t, changes, 3)
+	assert.Equal(t, TOKEN_UPDATED, changes[0].Action)
+	assert.Equal(t, acmeUpdated, changes[0].Token)
+	assert.Equal(t, TOKEN_ADDED, changes[1].Action)
+	assert.Equal(t, bar.URL, changes[1].URL)
+	assert.Equal(t, TOKEN_REMOVED, changes[2].Action)
+	assert.Equal(t, foo.URL, changes[2].URL)
+
+}
+
+func TestValidateDestinationLite(t *testing.T) {
+
+	// lite token accounts are validated without api calls
+	err := ValidateDestination(nil, "acc://abdafe3eb60d205905e10e5a2129e9567292646b968ecb7b/ACME", "acc://ACME")
+	assert.NoError(t, err)
+
+	// invalid checksum
+	err = ValidateDestination(nil, "acc://abdafe3eb60d205905e10e5a2129e9567292646b968ecb7c/ACME", "acc://ACME")
+	assert.True(t, errors.Is(err, ErrInvalidDestination))
+
+	// wrong token
+	err = ValidateDestination(nil, "acc://abdafe3eb60d205905e10e5a2129e9567292646b968ecb7b/foo.acme/tokens", "acc://ACME")
+	assert.True(t, errors.Is(err, ErrInvalidDestination))
+
+	// not a url
+	err = ValidateDestination(nil, "xxx://foo", "acc://ACME")
+	assert.True(t, errors.Is(err, ErrInvalidDestination))
+
+}
+
+// accountClient returns the account for every query
+type accountClient struct {
+	jsonrpc.RPCClient
+	account map[string]interface{}
+}
+
+func (c *accountClient) Call(ctx context.Context, method string, params ...interface{}) (*jsonrpc.RPCResponse, error) {
+	return &jsonrpc.RPCResponse{Result: map[string]interface{}{"data": c.account}}, nil
+}
+
+func TestValidateDestinationAccount(t *testing.T) {
+
+	a := &accumulate.AccumulateClient{}
+
+	// ADI
+	a.Client = &accountClient{account: map[string]interface{}{"type": "identity", "url": "acc://foo.acme"}}
+	err := ValidateDestination(a, "acc://foo.acme", "acc://ACME")
+	assert.True(t, errors.Is(err, ErrInvalidDestination))
+
+	// data account
+	a.Client = &accountClient{account: map[string]interface{}{"type": "dataAccount", "url": "acc://foo.acme/data"}}
+	err = ValidateDestination(a, "acc://foo.acme/data", "acc://ACME")
+	assert.True(t, errors.Is(err, ErrInvalidDestination))
+
+}
+
+func TestFindBurnLog(t *testing.T) {
+
+	txid := common.HexToHash("0xaa")
+	first := &evm.EventLog{TxID: txid, BlockHash: common.HexToHash("0x01"), LogIndex: 3}
+	second := &evm.EventLog{TxID: txid, BlockHash: common.HexToHash("0x01"), LogIndex: 5}
+	logs := []*evm.EventLog{first, second}
+
+	// TEST 1: several burns in the same tx are matched by log index
+	assert.Equal(t, first, FindBurnLog(logs, &schema.BurnEvent{EVMTxID: txid.Hex(), BlockHash: first.BlockHash.Hex(), LogIndex: 3}))
+	assert.Equal(t, second, FindBurnLog(logs, &schema.BurnEvent{EVMTxID: txid.Hex(), BlockHash: first.BlockHash.Hex(), LogIndex: 5}))
+
+	// TEST 2: unknown log index
+	assert.Nil(t, FindBurnLog(logs, &schema.BurnEvent{EVMTxID: txid.Hex(), BlockHash: first.BlockHash.Hex(), LogIndex: 4}))
+
+	// TEST 3: entry without block hash is matched by txid only
+	assert.Equal(t, first, FindBurnLog(logs, &schema.BurnEvent{EVMTxID: txid.Hex()}))
+
+}
+
+func TestValidateBurnTx(t *testing.T) {
+
+	l := &evm.EventLog{TxID: common.HexToHash("0xaa"), BlockHash: common.HexToHash("0x01")}
+
+	// TEST 1: successful tx in the block of the log
+	assert.NoError(t, ValidateBurnTx(&evm.Tx{TxHash: l.TxID.Hex(), BlockHash: l.BlockHash.Hex(), Status: types.ReceiptStatusSuccessful}, l))
+
+	// TEST 2: reverted tx
+	assert.Error(t, ValidateBurnTx(&evm.Tx{TxHash: l.TxID.Hex(), BlockHash: l.BlockHash.Hex(), Status: types.ReceiptStatusFailed}, l))
+
+	// TEST 3: tx from another block
+	assert.Error(t, ValidateBurnTx(&evm.Tx{TxHash: l.TxID.Hex(), BlockHash: common.HexToHash("0x02").Hex(), Status: types.ReceiptStatusSuccessful}, l))
+
+}
+
+func TestBurnEventPosition(t *testing.T) {
+
+	burn := &schema.BurnEvent{BlockHeight: 10, BlockHash: "0x01", LogIndex: 3}
+
+	// TEST 1: next log in the same block
+	assert.True(t, burn.IsBefore(10, 4))
+	assert.False(t, burn.IsBefore(10, 3))
+
+	// TEST 2: entry without block hash covers the whole block
+	legacy := &schema.BurnEvent{BlockHeight: 10}
+	assert.False(t, legacy.IsBefore(10, 100))
+	assert.True(t, legacy.IsBefore(11, 0))
+
+	// TEST 3: previous position is right before the log
+	previous := burn.Previous()
+	assert.True(t, previous.IsBefore(burn.Position()))
+	assert.False(t, previous.IsBefore(10, 2))
+
+	first := &schema.BurnEvent{BlockHeight: 10, BlockHash: "0x01", LogIndex: 0}
+	assert.True(t, first.Previous().IsBefore(first.Position()))
+	assert.False(t, first.Previous().IsBefore(9, 100))
+
+	assert.True(t, legacy.Previous().IsBefore(10, 0))
+	assert.False(t, legacy.Previous().IsBefore(9, 100))
+
+}
+
+func TestValidateDepositBurnTx(t *testing.T) {
+
+	entry := &schema.DepositEvent{TxID: "acc://5e3f2b0a@bridge.acme/1-TKN"}
+
+	burnTx := &accumulate.QueryTokenTxResponse{Type: accumulate.TX_TYPE_BURN_TOKENS, Data: &accumulate.TokenTx{Amount: "1000"}}
+	burnTx.Transaction.Header.Memo = entry.TxID
+
+	// TEST 1: burn of the deposit
+	assert.NoError(t, ValidateDepositBurnTx(burnTx, entry, 1000))
+
+	// TEST 2: amount mismatch
+	assert.Error(t, ValidateDepositBurnTx(burnTx, entry, 999))
+
+	// TEST 3: burn references another deposit
+	burnTx.Transaction.Header.Memo = "acc://11111111@bridge.acme/1-TKN"
+	assert.Error(t, ValidateDepositBurnTx(burnTx, entry, 1000))
+
+	// TEST 4: not a burn
+	burnTx.Transaction.Header.Memo = entry.TxID
+	burnTx.Type = accumulate.TX_TYPE_SEND_TOKENS
+	assert.Error(t, ValidateDepositBurnTx(burnTx, entry, 1000))
+
+}
+
+func TestValidateReleaseTx(t *testing.T) {
+
+	token := &schema.Token{URL: "acc://bridge.acme/TKN", Symbol: "TKN", Precision: 8, EVMAddress: "0x4E780D102AADECF1BdC06d91542cf91960538a2D", EVMDecimals: 8}
+
+	global.TokensLock.Lock()
+	global.Tokens.Items = []*schema.Token{token}
+	global.TokensLock.Unlock()
+	global.BridgeFees = schema.BridgeFees{}
+
+	l := &evm.EventLog{TxID: common.HexToHash("0xaa"), LogIndex: 3, Token: common.HexToAddress(token.EVMAddress), Amount: big.NewInt(1000), Destination: "acc://foo.acme/tokens"}
+
+	releaseTx := func(memo string) *accumulate.QueryTokenTxResponse {
+		tx := &accumulate.QueryTokenTxResponse{Type: accumulate.TX_TYPE_SEND_TOKENS, Data: &accumulate.TokenTx{}}
+		tx.Data.To = append(tx.Data.To, &accumulate.TokenTxTo{URL: "acc://foo.acme/tokens", Amount: "1000"})
+		tx.Transaction.Header.Memo = memo
+		return tx
+	}
+
+	// TEST 1: release tx references the burn
+	assert.NoError(t, ValidateReleaseTx(releaseTx(accumulate.GenerateReleaseMemo(1, l.TxID.Hex(), 3)), l, 1))
+
+	// TEST 2: release tx references another burn log
+	assert.Error(t, ValidateReleaseTx(releaseTx(accumulate.GenerateReleaseMemo(1, l.TxID.Hex(), 4)), l, 1))
+	assert.Error(t, ValidateReleaseTx(releaseTx("invalid"), l, 1))
+
+	// TEST 3: release tx queued before the upgrade has no memo and is validated by amount and destination
+	assert.NoError(t, ValidateReleaseTx(releaseTx(""), l, 1))
+
+	legacy := releaseTx("")
+	legacy.Data.To[0].Amount = "999"
+	assert.Error(t, ValidateReleaseTx(legacy, l, 1))
+
+	legacy = releaseTx("")
+	legacy.Data.To[0].URL = "acc://bar.acme/tokens"
+	assert.Error(t, ValidateReleaseTx(legacy, l, 1))
+
+}
+
+// dataSetClient serves data set queries from entries
+type dataSetClient struct {
+	jsonrpc.RPCClient
+	entries []*accumulate.DataEntry
+}
+
+func (c *dataSetClient) Call(ctx context.Context, method string, params ...interface{}) (*jsonrpc.RPCResponse, error) {
+
+	p := *params[0].(**accumulate.Params)
+
+	items := []*accumulate.DataEntry{}
+	if p.Expand {
+		for i := p.Start; i < p.Start+p.Count && i < int64(len(c.entries)); i++ {
+			items = append(items, c.entries[i])
+		}
+	}
+
+	return &jsonrpc.RPCResponse{Result: &accumulate.QueryDataSetResponse{Items: items, Total: int64(len(c.entries))}}, nil
+
+}
+
+func releaseEntry(t *testing.T, burn *schema.BurnEvent) *accumulate.DataEntry {
+
+	burnBytes, err := json.Marshal(burn)
+	assert.NoError(t, err)
+
+	entry := &accumulate.DataEntry{EntryHash: burn.EVMTxID}
+	entry.Entry.Type = "doubleHash"
+	entry.Entry.Data = []string{hex.EncodeToString([]byte(accumulate.RELEASE_QUEUE_VERSION)), hex.EncodeToString(burnBytes)}
+
+	return entry
+
+}
+
+func TestFindReleaseEntry(t *testing.T) {
+
+	client := &dataSetClient{}
+	for i := 0; i < 250; i++ {
+		burn := &schema.BurnEvent{EVMTxID: common.BigToHash(big.NewInt(int64(i))).Hex(), BlockHeight: int64(100 + i), BlockHash: "0x01", LogIndex: 1}
+		client.entries = append(client.entries, releaseEntry(t, burn))
+	}
+
+	a := &accumulate.AccumulateClient{Client: client, Validate: validator.New()}
+
+	// TEST 1: entry on the latest page
+	lock := &evm.EventLog{TxID: common.BigToHash(big.NewInt(240)), BlockHeight: 340, LogIndex: 1}
+	entry, err := FindReleaseEntry(a, "acc://bridge.acme/1-release", lock)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(340), entry.BlockHeight)
+
+	// TEST 2: entry on the first page
+	lock = &evm.EventLog{TxID: common.BigToHash(big.NewInt(10)), BlockHeight: 110, LogIndex: 1}
+	entry, err = FindReleaseEntry(a, "acc://bridge.acme/1-release", lock)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(110), entry.BlockHeight)
+
+	// TEST 3: another log of the tx is not queued, search stops at the lock position
+	lock = &evm.EventLog{TxID: common.BigToHash(big.NewInt(240)), BlockHeight: 340, LogIndex: 0}
+	_, err = FindReleaseEntry(a, "acc://bridge.acme/1-release", lock)
+	assert.True(t, errors.Is(err, ErrReleaseEntryNotFound))
+
+	// TEST 4: lock after the latest entry
+	lock = &evm.EventLog{TxID: common.BigToHash(big.NewInt(500)), BlockHeight: 600, LogIndex: 1}
+	_, err = FindReleaseEntry(a, "acc://bridge.acme/1-release", lock)
+	assert.True(t, errors.Is(err, ErrReleaseEntryNotFound))
+
+}
+
+func TestValidateRefund(t *testing.T) {
+
+	// plain transfer to the safe has no destination
+	lock := &evm.EventLog{TxID: common.HexToHash("0xaa"), BlockHash: common.HexToHash("0x01"), LogIndex: 3, Token: common.HexToAddress("0xbb"), Amount: big.NewInt(1000), Locked: true}
+	entry := &schema.BurnEvent{EVMTxID: lock.TxID.Hex(), BlockHash: lock.BlockHash.Hex(), LogIndex: 3, TokenAddress: lock.Token.Hex(), Amount: 1000, Exception: ErrInvalidDestination.Error()}
+
+	// TEST 1: transfer without destination is recorded as exception and can be refunded
+	assert.NoError(t, ValidateRefund(entry, lock))
+
+	// TEST 2: released transfer is not refunded
+	released := *entry
+	released.Exception = ""
+	released.TxHash = "0xcc"
+	assert.True(t, errors.Is(ValidateRefund(&released, lock), ErrNotRefundable))
+
+	// TEST 3: entry of another transfer
+	other := *entry
+	other.Amount = 999
+	assert.True(t, errors.Is(ValidateRefund(&other, lock), ErrNotRefundable))
+
+	other = *entry
+	other.LogIndex = 4
+	assert.True(t, errors.Is(ValidateRefund(&other, lock), ErrNotRefundable))
+
+	// TEST 4: bridge burns are not refunded
+	burn := *lock
+	burn.Locked = false
+	assert.True(t, errors.Is(ValidateRefund(entry, &burn), ErrNotRefundable))
+
+}